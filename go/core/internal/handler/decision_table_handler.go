@@ -22,7 +22,10 @@ func (h *DecisionTableHandler) RegisterRoutes(router gin.IRoutes) {
 	router.POST("/decision-tables/list", h.service.GetAllDecisionTables)
 	router.DELETE("/decision-table", h.service.ArchiveDecisionTable)
 	router.PUT("/decision-table/clone", h.service.CloneDecisionTable)
-	//TODO: Add routes to export/import decision table
-	//router.GET("/decision-table/export", h.service.ExportDecisionTable)
-	//router.POST("/decision-table/import", h.service.ImportDecisionTable)
+	router.POST("/decision-table/analyze", h.service.AnalyzeDecisionTable)
+	router.GET("/decision-table/test", h.service.RunDecisionTableTests)
+	router.GET("/decision-table/export", h.service.ExportDecisionTable)
+	router.POST("/decision-table/import", h.service.ImportDecisionTable)
+	router.GET("/decision-table/export/sheet", h.service.ExportDecisionTableSheet)
+	router.POST("/decision-table/import/sheet", h.service.ImportDecisionTableSheet)
 }
@@ -57,3 +57,15 @@ func GetNextVersionNumber(c *gin.Context, db *mongo.Database, nimbID string) (in
 func GetCommonSortOption() *options.FindOptions {
 	return options.Find().SetSort(bson.D{{Key: "audit.version", Value: -1}, {Key: "audit.minor_version", Value: -1}, {Key: "audit.updated_at", Value: -1}})
 }
+
+// versionFilter selects the current minor version of a version, or the given minor version, archived
+// or not, when minorVersion is set
+func versionFilter(nimbID string, version, minorVersion int) bson.M {
+	filter := bson.M{"nimb_id": nimbID, "audit.version": version}
+	if minorVersion == 0 {
+		filter["audit.is_archived"] = false
+	} else {
+		filter["audit.minor_version"] = minorVersion
+	}
+	return filter
+}
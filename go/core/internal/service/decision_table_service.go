@@ -1,7 +1,12 @@
 package service
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prithvirajv06/nimbus-uta/go/core/config"
@@ -9,8 +14,12 @@ import (
 	"github.com/prithvirajv06/nimbus-uta/go/core/internal/repository"
 	"github.com/prithvirajv06/nimbus-uta/go/core/internal/utils"
 	"github.com/prithvirajv06/nimbus-uta/go/core/pkg/database"
+	"github.com/prithvirajv06/nimbus-uta/go/core/pkg/dmn"
+	"github.com/prithvirajv06/nimbus-uta/go/core/pkg/dtable"
 	"github.com/prithvirajv06/nimbus-uta/go/core/pkg/messaging"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type DecisionTableService struct {
@@ -33,14 +42,24 @@ func (dts *DecisionTableService) CreateNewDecisionTable(c *gin.Context) {
 	if HandleError(c, err, "Unable to unmarshal payload") {
 		return
 	}
+	if checkCells(c, payload) {
+		return
+	}
 	repo := repository.NewGenericRepository[models.DecisionTable](c.Request.Context(), dts.mongo.Database, "decision_tables")
 	payload.NIMB_ID = utils.GenerateNIMBID("N_D_TABLE")
 	payload.Audit.SetInitialAudit(c)
+	if dts.checkRequirements(c, &payload) {
+		return
+	}
+	report, responded := runTestCases(c, payload)
+	if responded {
+		return
+	}
 	_, err = repo.InsertOne(payload)
 	if HandleError(c, err, "Failed to create decision table") {
 		return
 	}
-	RespondJSON(c, 201, "success", "Decision table created", payload)
+	RespondJSON(c, 201, "success", "Decision table created"+testSummary(report), payload)
 }
 
 func (dts *DecisionTableService) UpdateDecisionTable(c *gin.Context) {
@@ -49,19 +68,118 @@ func (dts *DecisionTableService) UpdateDecisionTable(c *gin.Context) {
 	if HandleError(c, err, "Unable to unmarshel payload") {
 		return
 	}
-	// Archive Old Version and Create New Version
-	repo := repository.NewGenericRepository[models.DecisionTable](c.Request.Context(), dts.mongo.Database, "decision_tables")
-	err = ArchiveEntity(c, repo, payload.NIMB_ID, payload.Audit.Version)
-	if HandleError(c, err, "Failed to archive old version of decision table") {
+	if dts.keepRulesMeta(c, &payload) || checkCells(c, payload) || dts.checkRequirements(c, &payload) {
 		return
 	}
-	payload.Audit.SetModifiedAudit(c)
-	payload.NoOfRows = len(payload.Rules)
-	_, err = repo.InsertOne(payload)
-	if HandleError(c, err, "Failed to create new version of decision table") {
+	report, responded := runTestCases(c, payload)
+	if responded {
+		return
+	}
+	if dts.saveNewMinorVersion(c, &payload) {
 		return
 	}
-	RespondJSON(c, 201, "success", "Decision table updated", payload)
+	RespondJSON(c, 201, "success", "Decision table updated"+testSummary(report), payload)
+}
+
+// keepRulesMeta carries the rule metadata of the current minor version over to a table saved
+// without any, as the editor saves it, so that validity periods and annotations survive the save.
+// checkCells then rejects the save when the rows no longer line up with the metadata. It reports
+// whether it responded.
+func (dts *DecisionTableService) keepRulesMeta(c *gin.Context, table *models.DecisionTable) bool {
+	if len(table.RulesMeta) > 0 {
+		return false
+	}
+	repo := repository.NewGenericRepository[models.DecisionTable](c.Request.Context(), dts.mongo.Database, "decision_tables")
+	current, err := repo.FindOne(bson.M{"nimb_id": table.NIMB_ID, "audit.is_archived": false, "audit.version": table.Audit.Version},
+		options.FindOne().SetProjection(bson.M{"rules_meta": 1}))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false
+	}
+	if HandleError(c, err, "Failed to fetch current version of decision table") {
+		return true
+	}
+	table.RulesMeta = current.RulesMeta
+	return false
+}
+
+// checkCells parses every input entry of the table and, when some do not parse, responds with
+// each of them by row and column. It reports whether it responded.
+func checkCells(c *gin.Context, table models.DecisionTable) bool {
+	switch strings.ToUpper(table.ExpressionLanguage) {
+	case "", dtable.LanguageFEEL, dtable.LanguageNimbus:
+	default:
+		c.JSON(400, gin.H{"error": "Invalid decision table", "details": fmt.Sprintf("unknown expression language %q, expected FEEL or NIMBUS", table.ExpressionLanguage)})
+		return true
+	}
+	problems := dtable.CheckCells(table)
+	if len(problems) == 0 {
+		return false
+	}
+	c.JSON(400, gin.H{"error": "Invalid decision table entries", "details": problems})
+	return true
+}
+
+// checkRequirements walks the tables the table requires and responds with 400 when one is missing
+// or when they lead back to the table. It reports whether it responded.
+func (dts *DecisionTableService) checkRequirements(c *gin.Context, table *models.DecisionTable) bool {
+	if len(table.Requires) == 0 {
+		return false
+	}
+	repo := repository.NewGenericRepository[models.DecisionTable](c.Request.Context(), dts.mongo.Database, "decision_tables")
+	_, err := dtable.Resolve(table, func(nimbID string, version int) (*models.DecisionTable, error) {
+		return repo.FindOne(bson.M{"nimb_id": nimbID, "audit.is_archived": false, "audit.version": version})
+	})
+	var cycle *dtable.CycleError
+	switch {
+	case errors.As(err, &cycle):
+		c.JSON(400, gin.H{"error": "Decision requirements form a cycle", "details": cycle.Path})
+		return true
+	case errors.Is(err, mongo.ErrNoDocuments):
+		c.JSON(400, gin.H{"error": "Invalid decision requirements", "details": err.Error()})
+		return true
+	}
+	return HandleError(c, err, "Failed to check decision requirements")
+}
+
+// runTestCases runs the test cases of a table about to be saved. A version with failing cases
+// cannot be a production candidate, so it then responds with 422 and the report. It reports
+// whether it responded.
+func runTestCases(c *gin.Context, table models.DecisionTable) (*dtable.TestReport, bool) {
+	if len(table.TestCases) == 0 {
+		return nil, false
+	}
+	report, err := dtable.RunTests(c.Request.Context(), table)
+	if err != nil {
+		c.JSON(422, gin.H{"error": "Decision table test cases cannot run", "details": err.Error()})
+		return nil, true
+	}
+	if report.Failed > 0 && table.Audit.IsProdCandidate {
+		c.JSON(422, gin.H{"error": "Test cases failed, the version cannot be a production candidate", "details": report})
+		return nil, true
+	}
+	return report, false
+}
+
+func testSummary(report *dtable.TestReport) string {
+	if report == nil {
+		return ""
+	}
+	return fmt.Sprintf(", %d of %d test cases passed", report.Passed, len(report.Results))
+}
+
+// saveNewMinorVersion archives the current minor version of the table and inserts the table as the
+// next one. It responds with the failure itself and reports whether it did.
+func (dts *DecisionTableService) saveNewMinorVersion(c *gin.Context, table *models.DecisionTable) bool {
+	// Archive Old Version and Create New Version
+	repo := repository.NewGenericRepository[models.DecisionTable](c.Request.Context(), dts.mongo.Database, "decision_tables")
+	err := ArchiveEntity(c, repo, table.NIMB_ID, table.Audit.Version)
+	if HandleError(c, err, "Failed to archive old version of decision table") {
+		return true
+	}
+	table.Audit.SetModifiedAudit(c)
+	table.NoOfRows = len(table.Rules)
+	_, err = repo.InsertOne(*table)
+	return HandleError(c, err, "Failed to create new version of decision table")
 }
 
 func (dts *DecisionTableService) GetDecisionTableByNIMBID(c *gin.Context) {
@@ -127,3 +245,112 @@ func (dts *DecisionTableService) CloneDecisionTable(c *gin.Context) {
 	}
 	RespondJSON(c, 201, "success", "Decision table cloned", origDT)
 }
+
+// ImportDecisionTable creates a decision table from a DMN file, sent as the multipart field file
+// or as the raw body. The decision query param picks a decision by id or name, and package_nimb_id
+// attaches a variable package.
+func (dts *DecisionTableService) ImportDecisionTable(c *gin.Context) {
+	body, err := readUpload(c)
+	if HandleError(c, err, "Unable to read DMN file") {
+		return
+	}
+	defs, err := dmn.Parse(bytes.NewReader(body))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid DMN file", "details": err.Error()})
+		return
+	}
+	decision, err := defs.FindDecision(c.Query("decision"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid DMN file", "details": err.Error()})
+		return
+	}
+	table, err := dmn.ToDecisionTable(decision)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid DMN file", "details": err.Error()})
+		return
+	}
+	if packageID := c.Query("package_nimb_id"); packageID != "" {
+		pkgRepo := repository.NewGenericRepository[models.VariablePackage](c.Request.Context(), dts.mongo.Database, "variable_packages")
+		pkg, err := pkgRepo.FindOne(bson.M{"nimb_id": packageID, "audit.is_archived": false})
+		if HandleError(c, err, "Failed to fetch variable package") {
+			return
+		}
+		table.VariablePackage = *pkg
+	}
+	if checkCells(c, *table) {
+		return
+	}
+	repo := repository.NewGenericRepository[models.DecisionTable](c.Request.Context(), dts.mongo.Database, "decision_tables")
+	table.NIMB_ID = utils.GenerateNIMBID("N_D_TABLE")
+	table.Audit.SetInitialAudit(c)
+	_, err = repo.InsertOne(*table)
+	if HandleError(c, err, "Failed to create decision table") {
+		return
+	}
+	RespondJSON(c, 201, "success", "Decision table imported", table)
+}
+
+// ExportDecisionTable downloads a decision table version as a DMN 1.3 file: its current minor
+// version, or the archived one given by minor_version
+func (dts *DecisionTableService) ExportDecisionTable(c *gin.Context) {
+	version, _ := strconv.Atoi(c.Query("version"))
+	minorVersion, _ := strconv.Atoi(c.Query("minor_version"))
+	repo := repository.NewGenericRepository[models.DecisionTable](c.Request.Context(), dts.mongo.Database, "decision_tables")
+	table, err := repo.FindOne(versionFilter(c.Query("nimb_id"), version, minorVersion))
+	if HandleError(c, err, "Failed to fetch decision table") {
+		return
+	}
+	var out bytes.Buffer
+	err = dmn.FromDecisionTable(*table).Write(&out)
+	if HandleError(c, err, "Failed to export decision table") {
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", table.Name+".dmn"))
+	c.Data(200, "application/xml", out.Bytes())
+}
+
+// readUpload returns the multipart field file when the request has one and the raw body otherwise
+func readUpload(c *gin.Context) ([]byte, error) {
+	if header, err := c.FormFile("file"); err == nil {
+		file, err := header.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return io.ReadAll(file)
+	}
+	return io.ReadAll(c.Request.Body)
+}
+
+// AnalyzeDecisionTable checks the posted table for overlapping, unreachable and duplicate rows and
+// for gaps, so authors see hit policy conflicts before the table is published
+func (dts *DecisionTableService) AnalyzeDecisionTable(c *gin.Context) {
+	var payload models.DecisionTable
+	err := c.ShouldBindJSON(&payload)
+	if HandleError(c, err, "Unable to unmarshal payload") {
+		return
+	}
+	analysis, err := dtable.Analyze(payload)
+	if err != nil {
+		c.JSON(422, gin.H{"error": "Decision table cannot be analyzed", "details": err.Error()})
+		return
+	}
+	RespondJSON(c, 200, "success", fmt.Sprintf("Decision table analyzed, %d findings", len(analysis.Findings)), analysis)
+}
+
+// RunDecisionTableTests runs the test cases stored with a decision table version and returns,
+// per case, whether it passed, the matched rows and the outputs
+func (dts *DecisionTableService) RunDecisionTableTests(c *gin.Context) {
+	version, _ := strconv.Atoi(c.Query("version"))
+	repo := repository.NewGenericRepository[models.DecisionTable](c.Request.Context(), dts.mongo.Database, "decision_tables")
+	table, err := repo.FindOne(bson.M{"nimb_id": c.Query("nimb_id"), "audit.is_archived": false, "audit.version": version})
+	if HandleError(c, err, "Failed to fetch decision table") {
+		return
+	}
+	report, err := dtable.RunTests(c.Request.Context(), *table)
+	if err != nil {
+		c.JSON(422, gin.H{"error": "Decision table test cases cannot run", "details": err.Error()})
+		return
+	}
+	RespondJSON(c, 200, "success", fmt.Sprintf("Test cases run, %d passed, %d failed", report.Passed, report.Failed), report)
+}
@@ -0,0 +1,396 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prithvirajv06/nimbus-uta/go/core/internal/models"
+	"github.com/prithvirajv06/nimbus-uta/go/core/internal/repository"
+	"github.com/prithvirajv06/nimbus-uta/go/core/pkg/dtable"
+	"github.com/prithvirajv06/nimbus-uta/go/core/pkg/spreadsheet"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Column kinds of the first header row of a decision table spreadsheet. The second header row
+// names the variable of each input and output column.
+const (
+	sheetInput      = "input"
+	sheetOutput     = "output"
+	sheetAnnotation = "annotation"
+	sheetValidFrom  = "valid_from"
+	sheetValidTo    = "valid_to"
+)
+
+// Spreadsheet rows taken by the two header rows
+const sheetHeaderRows = 2
+
+// ImportDecisionTableSheet replaces the columns and rules of a decision table with the contents of
+// a CSV or XLSX file and saves them as a new minor version. Every cell is checked first, and
+// problems are reported by spreadsheet row and column; the version's test cases then run as on
+// any other save.
+func (dts *DecisionTableService) ImportDecisionTableSheet(c *gin.Context) {
+	version, _ := strconv.Atoi(c.Query("version"))
+	repo := repository.NewGenericRepository[models.DecisionTable](c.Request.Context(), dts.mongo.Database, "decision_tables")
+	table, err := repo.FindOne(bson.M{"nimb_id": c.Query("nimb_id"), "audit.is_archived": false, "audit.version": version})
+	if HandleError(c, err, "Failed to fetch decision table") {
+		return
+	}
+	filename := ""
+	if header, err := c.FormFile("file"); err == nil {
+		filename = header.Filename
+	}
+	format, err := spreadsheet.Format(c.Query("format"), filename)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid spreadsheet", "details": err.Error()})
+		return
+	}
+	body, err := readUpload(c)
+	if HandleError(c, err, "Unable to read spreadsheet") {
+		return
+	}
+	rows, err := spreadsheet.Read(format, bytes.NewReader(body))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid spreadsheet", "details": err.Error()})
+		return
+	}
+	if problems := sheetToTable(table, rows); len(problems) > 0 {
+		c.JSON(400, gin.H{"error": "Spreadsheet import failed", "details": problems})
+		return
+	}
+	report, responded := runTestCases(c, *table)
+	if responded {
+		return
+	}
+	if dts.saveNewMinorVersion(c, table) {
+		return
+	}
+	RespondJSON(c, 201, "success", "Decision table imported"+testSummary(report), table)
+}
+
+// ExportDecisionTableSheet downloads a decision table version as CSV or XLSX, laid out the way
+// ImportDecisionTableSheet reads it
+func (dts *DecisionTableService) ExportDecisionTableSheet(c *gin.Context) {
+	format, err := spreadsheet.Format(c.DefaultQuery("format", spreadsheet.CSV), "")
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid format", "details": err.Error()})
+		return
+	}
+	version, _ := strconv.Atoi(c.Query("version"))
+	repo := repository.NewGenericRepository[models.DecisionTable](c.Request.Context(), dts.mongo.Database, "decision_tables")
+	table, err := repo.FindOne(bson.M{"nimb_id": c.Query("nimb_id"), "audit.is_archived": false, "audit.version": version})
+	if HandleError(c, err, "Failed to fetch decision table") {
+		return
+	}
+	var out bytes.Buffer
+	err = spreadsheet.Write(format, &out, table.Name, tableToSheet(*table))
+	if HandleError(c, err, "Failed to export decision table") {
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", table.Name+"."+format))
+	c.Data(200, spreadsheet.ContentType(format), out.Bytes())
+}
+
+// tableToSheet lays a table out as the kind row, the variable row and one row per rule. The
+// annotation and validity columns are only written when a rule has an annotation or validity dates.
+func tableToSheet(table models.DecisionTable) [][]string {
+	withAnnotations, withValidity := false, false
+	for _, meta := range table.RulesMeta {
+		withAnnotations = withAnnotations || meta.Annotation != ""
+		withValidity = withValidity || meta.ValidFrom != nil || meta.ValidTo != nil
+	}
+	kinds, keys := []string{}, []string{}
+	for _, column := range table.InputsColumns {
+		kinds, keys = append(kinds, sheetInput), append(keys, column.VarKey)
+	}
+	for _, column := range table.OutputsColumns {
+		kinds, keys = append(kinds, sheetOutput), append(keys, column.VarKey)
+	}
+	if withAnnotations {
+		kinds, keys = append(kinds, sheetAnnotation), append(keys, "")
+	}
+	if withValidity {
+		kinds, keys = append(kinds, sheetValidFrom, sheetValidTo), append(keys, "", "")
+	}
+	rows := [][]string{kinds, keys}
+	width := len(table.InputsColumns) + len(table.OutputsColumns)
+	for i, cells := range table.Rules {
+		row := make([]string, len(kinds))
+		for c := 0; c < width && c < len(cells); c++ {
+			row[c] = sheetCellText(cells[c].Value)
+		}
+		if i < len(table.RulesMeta) {
+			meta, c := table.RulesMeta[i], width
+			if withAnnotations {
+				row[c] = meta.Annotation
+				c++
+			}
+			if withValidity {
+				row[c], row[c+1] = sheetDateText(meta.ValidFrom), sheetDateText(meta.ValidTo)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func sheetDateText(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return dtable.FormatDate(*date)
+}
+
+func sheetCellText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(raw)
+	}
+}
+
+// sheetColumn is a spreadsheet column mapped onto the table
+type sheetColumn struct {
+	index    int
+	kind     string
+	variable models.Variables
+}
+
+// sheetToTable reads spreadsheet rows into the columns and rules of table. Header variables are
+// looked up in the table's variable package, and in its current columns when it has none. It
+// returns every problem found and leaves table untouched when there is one.
+func sheetToTable(table *models.DecisionTable, rows [][]string) []models.SheetError {
+	if len(rows) < sheetHeaderRows {
+		return []models.SheetError{{Row: 1, Message: "expected a row of column kinds (input, output, annotation, valid_from, valid_to) followed by a row of variable keys"}}
+	}
+	columns, problems := sheetColumns(table, rows[0], rows[1])
+	var inputs, outputs []sheetColumn
+	annotation := -1
+	var validity []sheetColumn
+	for _, column := range columns {
+		switch column.kind {
+		case sheetInput:
+			inputs = append(inputs, column)
+		case sheetOutput:
+			outputs = append(outputs, column)
+		case sheetAnnotation:
+			annotation = column.index
+		case sheetValidFrom, sheetValidTo:
+			validity = append(validity, column)
+		}
+	}
+	if len(problems) == 0 && (len(inputs) == 0 || len(outputs) == 0) {
+		problems = append(problems, models.SheetError{Row: 1, Message: "at least one input and one output column are required"})
+	}
+	if len(problems) > 0 {
+		return problems
+	}
+
+	var rules [][]models.Variables
+	var metas []models.RuleMeta
+	for r := sheetHeaderRows; r < len(rows); r++ {
+		if blankRow(rows[r]) {
+			continue
+		}
+		cells := make([]models.Variables, 0, len(inputs)+len(outputs))
+		for _, column := range inputs {
+			cell, err := sheetInputCell(table.ExpressionLanguage, column.variable, sheetCell(rows[r], column.index))
+			if err != nil {
+				problems = append(problems, sheetError(r, column, err.Error()))
+			}
+			cells = append(cells, cell)
+		}
+		for _, column := range outputs {
+			cell, err := sheetOutputCell(column.variable, sheetCell(rows[r], column.index))
+			if err != nil {
+				problems = append(problems, sheetError(r, column, err.Error()))
+			}
+			cells = append(cells, cell)
+		}
+		meta := models.RuleMeta{Annotation: sheetCell(rows[r], annotation)}
+		for _, column := range validity {
+			text := sheetCell(rows[r], column.index)
+			if text == "" {
+				continue
+			}
+			date, err := dtable.ParseDate(text)
+			if err != nil {
+				problems = append(problems, models.SheetError{Row: r + 1, Column: spreadsheet.ColumnName(column.index), Header: column.kind, Message: err.Error()})
+				continue
+			}
+			if column.kind == sheetValidFrom {
+				meta.ValidFrom = &date
+			} else {
+				meta.ValidTo = &date
+			}
+		}
+		if meta.ValidFrom != nil && meta.ValidTo != nil && !meta.ValidTo.After(*meta.ValidFrom) {
+			problems = append(problems, models.SheetError{Row: r + 1, Header: sheetValidTo,
+				Message: fmt.Sprintf("valid_to %s must come after valid_from %s", dtable.FormatDate(*meta.ValidTo), dtable.FormatDate(*meta.ValidFrom))})
+		}
+		rules = append(rules, cells)
+		metas = append(metas, meta)
+	}
+	if len(problems) > 0 {
+		return problems
+	}
+	table.InputsColumns = nil
+	for _, column := range inputs {
+		table.InputsColumns = append(table.InputsColumns, column.variable)
+	}
+	table.OutputsColumns = nil
+	for _, column := range outputs {
+		table.OutputsColumns = append(table.OutputsColumns, column.variable)
+	}
+	table.Rules = rules
+	table.RulesMeta = metas
+	table.NoOfInputs = len(inputs)
+	table.NoOfOutputs = len(outputs)
+	table.NoOfRows = len(rules)
+	return nil
+}
+
+// sheetColumns maps the header rows onto variables. Columns with both header cells blank are ignored.
+func sheetColumns(table *models.DecisionTable, kinds, keys []string) ([]sheetColumn, []models.SheetError) {
+	var variables []models.Variables
+	flattenVariables(table.VariablePackage.Variables, &variables)
+	if len(variables) == 0 {
+		variables = append(append(variables, table.InputsColumns...), table.OutputsColumns...)
+	}
+	var columns []sheetColumn
+	var problems []models.SheetError
+	seen := map[string]bool{}
+	for c := 0; c < len(kinds) || c < len(keys); c++ {
+		kind := strings.ToLower(sheetCell(kinds, c))
+		key := sheetCell(keys, c)
+		if kind == "" && key == "" {
+			continue
+		}
+		column := sheetColumn{index: c, kind: kind}
+		switch kind {
+		case sheetAnnotation, sheetValidFrom, sheetValidTo:
+			columns = append(columns, column)
+			continue
+		case sheetInput, sheetOutput:
+		default:
+			problems = append(problems, models.SheetError{Row: 1, Column: spreadsheet.ColumnName(c), Header: key,
+				Message: fmt.Sprintf("unknown column kind %q, expected input, output, annotation, valid_from or valid_to", sheetCell(kinds, c))})
+			continue
+		}
+		variable, ok := findSheetVariable(variables, key)
+		if !ok {
+			problems = append(problems, models.SheetError{Row: 2, Column: spreadsheet.ColumnName(c), Header: key,
+				Message: fmt.Sprintf("variable %q is not in variable package %s", key, table.VariablePackage.PackageName)})
+			continue
+		}
+		if seen[kind+variable.VarKey] {
+			problems = append(problems, models.SheetError{Row: 2, Column: spreadsheet.ColumnName(c), Header: key,
+				Message: fmt.Sprintf("variable %q is already an %s column", key, kind)})
+			continue
+		}
+		seen[kind+variable.VarKey] = true
+		variable.Children = nil
+		variable.Value = nil
+		column.variable = variable
+		columns = append(columns, column)
+	}
+	return columns, problems
+}
+
+func flattenVariables(variables []models.Variables, out *[]models.Variables) {
+	for _, variable := range variables {
+		*out = append(*out, variable)
+		flattenVariables(variable.Children, out)
+	}
+}
+
+// findSheetVariable matches a header against variable keys, with or without the data prefix,
+// and then against labels ignoring case
+func findSheetVariable(variables []models.Variables, header string) (models.Variables, bool) {
+	for _, variable := range variables {
+		if variable.VarKey == header || variable.VarKey == "data."+header {
+			return variable, true
+		}
+	}
+	for _, variable := range variables {
+		if variable.Label != "" && strings.EqualFold(variable.Label, header) {
+			return variable, true
+		}
+	}
+	return models.Variables{}, false
+}
+
+// sheetInputCell turns an input entry into a cell. Blank entries match anything, and boolean
+// columns hold true or false the way the editor stores them.
+func sheetInputCell(language string, column models.Variables, text string) (models.Variables, error) {
+	cell := column
+	if text == "" || text == "-" {
+		cell.Value = "-"
+		return cell, nil
+	}
+	if column.Type == "boolean" {
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return cell, fmt.Errorf("expected true, false or - but found %q", text)
+		}
+		cell.Value = b
+		return cell, nil
+	}
+	cell.Value = text
+	return cell, dtable.CheckCell(language, text)
+}
+
+// sheetOutputCell turns an output entry into a cell, checking it against the column type
+func sheetOutputCell(column models.Variables, text string) (models.Variables, error) {
+	cell := column
+	cell.Value = text
+	if text == "" || strings.EqualFold(text, "null") {
+		return cell, nil
+	}
+	switch column.Type {
+	case "number":
+		if _, err := strconv.ParseFloat(text, 64); err != nil {
+			return cell, fmt.Errorf("expected a number but found %q", text)
+		}
+	case "boolean":
+		if _, err := strconv.ParseBool(text); err != nil {
+			return cell, fmt.Errorf("expected true or false but found %q", text)
+		}
+	}
+	return cell, nil
+}
+
+func sheetError(rowIndex int, column sheetColumn, message string) models.SheetError {
+	return models.SheetError{Row: rowIndex + 1, Column: spreadsheet.ColumnName(column.index), Header: column.variable.VarKey, Message: message}
+}
+
+func sheetCell(row []string, index int) string {
+	if index < 0 || index >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[index])
+}
+
+func blankRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+// Package dmn converts decision tables to and from DMN 1.3 XML. Import also reads the DMN 1.1 and
+// 1.2 files older modelers produce, since elements are matched by local name in any namespace.
+package dmn
+
+import "encoding/xml"
+
+// Namespace written on export
+const Namespace = "https://www.omg.org/spec/DMN/20191111/MODEL/"
+
+type Definitions struct {
+	XMLName   xml.Name   `xml:"definitions"`
+	Xmlns     string     `xml:"xmlns,attr,omitempty"`
+	ID        string     `xml:"id,attr"`
+	Name      string     `xml:"name,attr"`
+	Namespace string     `xml:"namespace,attr"`
+	Decisions []Decision `xml:"decision"`
+}
+
+type Decision struct {
+	ID                      string                   `xml:"id,attr"`
+	Name                    string                   `xml:"name,attr"`
+	Description             string                   `xml:"description,omitempty"`
+	InformationRequirements []InformationRequirement `xml:"informationRequirement"`
+	DecisionTable           *DecisionTable           `xml:"decisionTable"`
+}
+
+type InformationRequirement struct {
+	ID               string     `xml:"id,attr,omitempty"`
+	RequiredInput    *Reference `xml:"requiredInput,omitempty"`
+	RequiredDecision *Reference `xml:"requiredDecision,omitempty"`
+}
+
+type Reference struct {
+	Href string `xml:"href,attr"`
+}
+
+type DecisionTable struct {
+	ID          string       `xml:"id,attr"`
+	HitPolicy   string       `xml:"hitPolicy,attr,omitempty"`
+	Aggregation string       `xml:"aggregation,attr,omitempty"`
+	Inputs      []Input      `xml:"input"`
+	Outputs     []Output     `xml:"output"`
+	Annotations []Annotation `xml:"annotation"`
+	Rules       []Rule       `xml:"rule"`
+}
+
+type Input struct {
+	ID              string          `xml:"id,attr"`
+	Label           string          `xml:"label,attr,omitempty"`
+	InputExpression InputExpression `xml:"inputExpression"`
+	InputValues     *UnaryTests     `xml:"inputValues,omitempty"`
+}
+
+type InputExpression struct {
+	ID      string `xml:"id,attr"`
+	TypeRef string `xml:"typeRef,attr,omitempty"`
+	Text    string `xml:"text"`
+}
+
+type Output struct {
+	ID           string      `xml:"id,attr"`
+	Label        string      `xml:"label,attr,omitempty"`
+	Name         string      `xml:"name,attr,omitempty"`
+	TypeRef      string      `xml:"typeRef,attr,omitempty"`
+	OutputValues *UnaryTests `xml:"outputValues,omitempty"`
+}
+
+type UnaryTests struct {
+	ID   string `xml:"id,attr,omitempty"`
+	Text string `xml:"text"`
+}
+
+type Annotation struct {
+	Name string `xml:"name,attr"`
+}
+
+type Rule struct {
+	ID                string            `xml:"id,attr"`
+	Description       string            `xml:"description,omitempty"`
+	InputEntries      []UnaryTests      `xml:"inputEntry"`
+	OutputEntries     []UnaryTests      `xml:"outputEntry"`
+	AnnotationEntries []AnnotationEntry `xml:"annotationEntry"`
+}
+
+type AnnotationEntry struct {
+	Text string `xml:"text"`
+}
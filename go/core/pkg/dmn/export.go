@@ -0,0 +1,201 @@
+package dmn
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/prithvirajv06/nimbus-uta/go/core/internal/models"
+)
+
+// FromDecisionTable converts a decision table into a DMN 1.3 document with one decision. Element
+// ids derive from the table's NIMB_ID and positions, except rule ids kept from an earlier import.
+func FromDecisionTable(table models.DecisionTable) *Definitions {
+	hitPolicy, aggregation := hitPolicyToDMN(table.HitPolicy)
+	dt := &DecisionTable{
+		ID:          "DecisionTable_" + table.NIMB_ID,
+		HitPolicy:   hitPolicy,
+		Aggregation: aggregation,
+	}
+	for i, column := range table.InputsColumns {
+		input := Input{
+			ID:    fmt.Sprintf("Input_%d", i+1),
+			Label: column.Label,
+			InputExpression: InputExpression{
+				ID:      fmt.Sprintf("InputExpression_%d", i+1),
+				TypeRef: typeToDMN(column.Type),
+				Text:    strings.TrimPrefix(column.VarKey, "data."),
+			},
+		}
+		if values := allowedValuesText(column.Value); values != "" {
+			input.InputValues = &UnaryTests{Text: values}
+		}
+		dt.Inputs = append(dt.Inputs, input)
+	}
+	for i, column := range table.OutputsColumns {
+		output := Output{
+			ID:      fmt.Sprintf("Output_%d", i+1),
+			Label:   column.Label,
+			Name:    strings.TrimPrefix(column.VarKey, "data."),
+			TypeRef: typeToDMN(column.Type),
+		}
+		if values := allowedValuesText(column.Value); values != "" {
+			output.OutputValues = &UnaryTests{Text: values}
+		}
+		dt.Outputs = append(dt.Outputs, output)
+	}
+	hasAnnotations := false
+	for i, cells := range table.Rules {
+		meta := models.RuleMeta{}
+		if i < len(table.RulesMeta) {
+			meta = table.RulesMeta[i]
+		}
+		rule := Rule{ID: meta.ID}
+		if rule.ID == "" {
+			rule.ID = fmt.Sprintf("Rule_%d", i+1)
+		}
+		for c := range table.InputsColumns {
+			text := "-"
+			if c < len(cells) {
+				if text = entryText(cells[c].Value, ""); text == "" {
+					text = "-"
+				}
+			}
+			rule.InputEntries = append(rule.InputEntries, UnaryTests{ID: fmt.Sprintf("InputEntry_%d_%d", i+1, c+1), Text: text})
+		}
+		for o, column := range table.OutputsColumns {
+			text := ""
+			if idx := len(table.InputsColumns) + o; idx < len(cells) {
+				text = entryText(cells[idx].Value, column.Type)
+			}
+			rule.OutputEntries = append(rule.OutputEntries, UnaryTests{ID: fmt.Sprintf("OutputEntry_%d_%d", i+1, o+1), Text: text})
+		}
+		rule.AnnotationEntries = []AnnotationEntry{{Text: meta.Annotation}}
+		hasAnnotations = hasAnnotations || meta.Annotation != ""
+		dt.Rules = append(dt.Rules, rule)
+	}
+	if hasAnnotations {
+		dt.Annotations = []Annotation{{Name: "Annotation"}}
+	} else {
+		for i := range dt.Rules {
+			dt.Rules[i].AnnotationEntries = nil
+		}
+	}
+	return &Definitions{
+		Xmlns:     Namespace,
+		ID:        "Definitions_" + table.NIMB_ID,
+		Name:      table.Name,
+		Namespace: "https://nimbus-uta/dmn/" + table.NIMB_ID,
+		Decisions: []Decision{{
+			ID:            "Decision_" + table.NIMB_ID,
+			Name:          table.Name,
+			Description:   table.Description,
+			DecisionTable: dt,
+		}},
+	}
+}
+
+// Write encodes the document as indented XML with a declaration
+func (d *Definitions) Write(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(d); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// entryText renders a cell as FEEL text. Values of string columns that are not yet string
+// literals are quoted, so entries typed without quotes in the editor stay strings in DMN tools.
+func entryText(value interface{}, columnType string) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		text := strings.TrimSpace(v)
+		if columnType == "string" && text != "" && text != "null" && !strings.HasPrefix(text, `"`) {
+			quoted, _ := json.Marshal(text)
+			return string(quoted)
+		}
+		return text
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(raw)
+	}
+}
+
+// allowedValuesText renders a column's allowed values, given as a list or a comma separated
+// string. A single sample value, as variable packages store, is not a list of allowed values.
+func allowedValuesText(value interface{}) string {
+	switch v := value.(type) {
+	case []interface{}:
+		texts := make([]string, len(v))
+		for i, item := range v {
+			texts[i] = entryText(item, "")
+			if _, isString := item.(string); isString {
+				quoted, _ := json.Marshal(item)
+				texts[i] = string(quoted)
+			}
+		}
+		return strings.Join(texts, ",")
+	case string:
+		if strings.Contains(v, ",") && !strings.HasPrefix(v, "[") && !strings.HasPrefix(v, "{") {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+func hitPolicyToDMN(policy string) (string, string) {
+	normalized := strings.Join(strings.Fields(strings.ToUpper(strings.ReplaceAll(policy, "_", " "))), " ")
+	switch normalized {
+	case "", "U":
+		return "UNIQUE", ""
+	case "F":
+		return "FIRST", ""
+	case "P":
+		return "PRIORITY", ""
+	case "A":
+		return "ANY", ""
+	case "R":
+		return "RULE ORDER", ""
+	case "O":
+		return "OUTPUT ORDER", ""
+	case "C", "ALL":
+		return "COLLECT", ""
+	case "SUM", "COLLECT SUM", "C+":
+		return "COLLECT", "SUM"
+	case "MIN", "COLLECT MIN", "C<":
+		return "COLLECT", "MIN"
+	case "MAX", "COLLECT MAX", "C>":
+		return "COLLECT", "MAX"
+	case "COUNT", "COLLECT COUNT", "C#":
+		return "COLLECT", "COUNT"
+	}
+	return normalized, ""
+}
+
+func typeToDMN(varType string) string {
+	switch varType {
+	case "number", "string", "boolean", "date", "dateTime", "time":
+		return varType
+	case "":
+		return ""
+	default:
+		return "Any"
+	}
+}
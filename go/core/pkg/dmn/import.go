@@ -0,0 +1,161 @@
+package dmn
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/prithvirajv06/nimbus-uta/go/core/internal/models"
+)
+
+// Parse decodes a DMN document
+func Parse(r io.Reader) (*Definitions, error) {
+	var defs Definitions
+	if err := xml.NewDecoder(r).Decode(&defs); err != nil {
+		return nil, fmt.Errorf("invalid DMN document: %w", err)
+	}
+	return &defs, nil
+}
+
+// FindDecision returns the decision with the given id or name, or the first decision holding a
+// decision table when ref is empty
+func (d *Definitions) FindDecision(ref string) (*Decision, error) {
+	for i := range d.Decisions {
+		decision := &d.Decisions[i]
+		if decision.DecisionTable == nil {
+			continue
+		}
+		if ref == "" || decision.ID == ref || decision.Name == ref {
+			return decision, nil
+		}
+	}
+	if ref == "" {
+		return nil, fmt.Errorf("DMN document holds no decision table")
+	}
+	return nil, fmt.Errorf("DMN document holds no decision table named %q", ref)
+}
+
+// ToDecisionTable converts a DMN decision into a decision table. Input expressions and output
+// names become variable keys under data, entries are kept as their FEEL text, and the allowed
+// output values are kept on the output column, where they give the PRIORITY order.
+func ToDecisionTable(decision *Decision) (*models.DecisionTable, error) {
+	dt := decision.DecisionTable
+	if dt == nil {
+		return nil, fmt.Errorf("decision %q holds no decision table", decision.Name)
+	}
+	table := &models.DecisionTable{
+		Name:        decision.Name,
+		Description: strings.TrimSpace(decision.Description),
+		HitPolicy:   hitPolicyFromDMN(dt.HitPolicy, dt.Aggregation),
+	}
+	if table.Name == "" {
+		table.Name = decision.ID
+	}
+	for _, input := range dt.Inputs {
+		column := models.Variables{
+			VarKey: varKey(input.InputExpression.Text),
+			Label:  input.Label,
+			Type:   typeFromDMN(input.InputExpression.TypeRef),
+		}
+		if column.Label == "" {
+			column.Label = strings.TrimSpace(input.InputExpression.Text)
+		}
+		if input.InputValues != nil {
+			column.Value = strings.TrimSpace(input.InputValues.Text)
+		}
+		table.InputsColumns = append(table.InputsColumns, column)
+	}
+	for _, output := range dt.Outputs {
+		name := output.Name
+		if name == "" {
+			name = output.ID
+		}
+		column := models.Variables{
+			VarKey: varKey(name),
+			Label:  output.Label,
+			Type:   typeFromDMN(output.TypeRef),
+		}
+		if column.Label == "" {
+			column.Label = name
+		}
+		if output.OutputValues != nil {
+			column.Value = strings.TrimSpace(output.OutputValues.Text)
+		}
+		table.OutputsColumns = append(table.OutputsColumns, column)
+	}
+	for i, rule := range dt.Rules {
+		if len(rule.InputEntries) != len(dt.Inputs) || len(rule.OutputEntries) != len(dt.Outputs) {
+			return nil, fmt.Errorf("rule %d has %d input and %d output entries, expected %d and %d",
+				i+1, len(rule.InputEntries), len(rule.OutputEntries), len(dt.Inputs), len(dt.Outputs))
+		}
+		cells := make([]models.Variables, 0, len(dt.Inputs)+len(dt.Outputs))
+		for c, entry := range rule.InputEntries {
+			cell := table.InputsColumns[c]
+			cell.Value = strings.TrimSpace(entry.Text)
+			if cell.Value == "" {
+				cell.Value = "-"
+			}
+			cells = append(cells, cell)
+		}
+		for o, entry := range rule.OutputEntries {
+			cell := table.OutputsColumns[o]
+			cell.Value = strings.TrimSpace(entry.Text)
+			cells = append(cells, cell)
+		}
+		table.Rules = append(table.Rules, cells)
+		table.RulesMeta = append(table.RulesMeta, models.RuleMeta{ID: rule.ID, Annotation: ruleAnnotation(rule)})
+	}
+	table.NoOfInputs = len(table.InputsColumns)
+	table.NoOfOutputs = len(table.OutputsColumns)
+	table.NoOfRows = len(table.Rules)
+	return table, nil
+}
+
+// ruleAnnotation reads the rule's description, which Camunda modelers write, or else its
+// annotation entries, which DMN 1.3 modelers write, joined when there are several columns
+func ruleAnnotation(rule Rule) string {
+	if text := strings.TrimSpace(rule.Description); text != "" {
+		return text
+	}
+	var texts []string
+	for _, entry := range rule.AnnotationEntries {
+		if text := strings.TrimSpace(entry.Text); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, " | ")
+}
+
+// varKey maps a DMN input expression or output name to a variable key under data
+func varKey(expr string) string {
+	expr = strings.TrimSpace(expr)
+	if expr == "data" || strings.HasPrefix(expr, "data.") {
+		return expr
+	}
+	return "data." + expr
+}
+
+func hitPolicyFromDMN(hitPolicy, aggregation string) string {
+	if hitPolicy == "" {
+		hitPolicy = "UNIQUE"
+	}
+	if hitPolicy == "COLLECT" && aggregation != "" {
+		// The editor names aggregated COLLECT by its aggregation alone
+		return aggregation
+	}
+	return hitPolicy
+}
+
+func typeFromDMN(typeRef string) string {
+	switch strings.ToLower(typeRef) {
+	case "number", "integer", "long", "double", "decimal", "int":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "string":
+		return "string"
+	default:
+		return typeRef
+	}
+}
@@ -0,0 +1,108 @@
+package dmn
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/prithvirajv06/nimbus-uta/go/core/internal/models"
+	"github.com/prithvirajv06/nimbus-uta/go/core/pkg/dtable"
+)
+
+// Camunda Modeler style: DMN 1.3 namespace, rule annotations kept in description
+const camundaSample = `<?xml version="1.0" encoding="UTF-8"?>
+<definitions xmlns="https://www.omg.org/spec/DMN/20191111/MODEL/" id="Definitions_1" name="Loan" namespace="http://camunda.org/schema/1.0/dmn">
+  <decision id="LoanRisk" name="Loan Risk">
+    <decisionTable id="DecisionTable_1" hitPolicy="FIRST">
+      <input id="Input_1" label="Age">
+        <inputExpression id="InputExpression_1" typeRef="integer"><text>applicant.age</text></inputExpression>
+      </input>
+      <input id="Input_2" label="Country">
+        <inputExpression id="InputExpression_2" typeRef="string"><text>applicant.country</text></inputExpression>
+      </input>
+      <output id="Output_1" label="Risk" name="risk" typeRef="string" />
+      <rule id="Rule_young">
+        <description>Young applicants</description>
+        <inputEntry id="E1"><text>&lt; 25</text></inputEntry>
+        <inputEntry id="E2"><text>-</text></inputEntry>
+        <outputEntry id="E3"><text>"HIGH"</text></outputEntry>
+      </rule>
+      <rule id="Rule_rest">
+        <inputEntry id="E4"><text>[25..120]</text></inputEntry>
+        <inputEntry id="E5"><text>"IN","US"</text></inputEntry>
+        <outputEntry id="E6"><text>"LOW"</text></outputEntry>
+      </rule>
+    </decisionTable>
+  </decision>
+</definitions>`
+
+// Kogito and Trisotech style: DMN 1.2 namespace, annotation columns, several decisions
+const kogitoSample = `<?xml version="1.0" encoding="UTF-8"?>
+<dmn:definitions xmlns:dmn="http://www.omg.org/spec/DMN/20180521/MODEL/" id="_defs" name="Fees" namespace="https://kiegroup.org/dmn/fees">
+  <dmn:decision id="_summary" name="Summary">
+    <dmn:literalExpression><dmn:text>"n/a"</dmn:text></dmn:literalExpression>
+  </dmn:decision>
+  <dmn:decision id="_fees" name="Fees">
+    <dmn:informationRequirement id="_ir1"><dmn:requiredInput href="#_order"/></dmn:informationRequirement>
+    <dmn:decisionTable id="_fees_table" hitPolicy="COLLECT" aggregation="SUM">
+      <dmn:input id="_in1" label="Amount">
+        <dmn:inputExpression id="_in1_expr" typeRef="number"><dmn:text>order.amount</dmn:text></dmn:inputExpression>
+      </dmn:input>
+      <dmn:output id="_out1" name="fee" typeRef="number"/>
+      <dmn:annotation name="Reason"/>
+      <dmn:rule id="_r1">
+        <dmn:inputEntry id="_r1_i1"><dmn:text>&gt; 100</dmn:text></dmn:inputEntry>
+        <dmn:outputEntry id="_r1_o1"><dmn:text>5</dmn:text></dmn:outputEntry>
+        <dmn:annotationEntry><dmn:text>Large order</dmn:text></dmn:annotationEntry>
+      </dmn:rule>
+      <dmn:rule id="_r2">
+        <dmn:inputEntry id="_r2_i1"><dmn:text></dmn:text></dmn:inputEntry>
+        <dmn:outputEntry id="_r2_o1"><dmn:text>1</dmn:text></dmn:outputEntry>
+        <dmn:annotationEntry><dmn:text></dmn:text></dmn:annotationEntry>
+      </dmn:rule>
+    </dmn:decisionTable>
+  </dmn:decision>
+</dmn:definitions>`
+
+// TestRoundTrip imports each sample, checks that its entries parse as FEEL, exports it again and
+// imports the export, expecting the two imported tables to be equal
+func TestRoundTrip(t *testing.T) {
+	for name, sample := range map[string]string{"camunda": camundaSample, "kogito": kogitoSample} {
+		t.Run(name, func(t *testing.T) {
+			first := importSample(t, strings.NewReader(sample))
+			first.NIMB_ID = "N_D_TABLE_" + name
+			for _, problem := range dtable.CheckCells(*first) {
+				t.Errorf("entry does not parse: %v", problem)
+			}
+			var out bytes.Buffer
+			if err := FromDecisionTable(*first).Write(&out); err != nil {
+				t.Fatalf("export failed: %v", err)
+			}
+			second := importSample(t, &out)
+			second.NIMB_ID = first.NIMB_ID
+			if !reflect.DeepEqual(first, second) {
+				t.Errorf("round trip changed the table\n before: %+v\n after:  %+v", *first, *second)
+			}
+		})
+	}
+}
+
+// importSample reads the first decision table of a DMN document
+func importSample(t *testing.T, r io.Reader) *models.DecisionTable {
+	t.Helper()
+	defs, err := Parse(r)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	decision, err := defs.FindDecision("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	table, err := ToDecisionTable(decision)
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	return table
+}
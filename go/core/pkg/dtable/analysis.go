@@ -0,0 +1,430 @@
+package dtable
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prithvirajv06/nimbus-uta/go/core/internal/models"
+	"github.com/prithvirajv06/nimbus-uta/go/core/pkg/feel"
+)
+
+// Kinds of analysis findings
+const (
+	FindingOverlap     = "overlap"
+	FindingGap         = "gap"
+	FindingUnreachable = "unreachable"
+	FindingDuplicate   = "duplicate"
+)
+
+// Limits that keep the analysis of large tables bounded. Past them, gaps and unreachable rows
+// may be missed and the analysis is marked truncated.
+const (
+	maxGapFindings = 50
+	maxBoxes       = 20000
+)
+
+// Finding is a problem found by Analyze. Rows are 1-based rule numbers; Inputs describes, per
+// input variable, the values a gap leaves uncovered or that overlapping rows share. Period tells
+// when a gap exists, for tables whose rows have validity periods.
+type Finding struct {
+	Kind    string            `json:"kind"`
+	Rows    []int             `json:"rows,omitempty"`
+	Inputs  map[string]string `json:"inputs,omitempty"`
+	Period  string            `json:"period,omitempty"`
+	Message string            `json:"message"`
+}
+
+// Analysis is the result of Analyze
+type Analysis struct {
+	HitPolicy string    `json:"hit_policy"`
+	Findings  []Finding `json:"findings"`
+	Truncated bool      `json:"truncated,omitempty"`
+}
+
+// box is the input space a row matches: one set of values per input column
+type box []feel.Set
+
+type analyzer struct {
+	table     models.DecisionTable
+	universe  box
+	rows      []box
+	outputs   [][]interface{}
+	validity  []validity
+	truncated bool
+}
+
+// Analyze checks a FEEL table before it is published. It reports rows that overlap under UNIQUE,
+// or under ANY with different outputs, rows no input can reach under FIRST, duplicate rows and
+// gaps: inputs no row matches. Missing (null) inputs are left out of the analysis, and each
+// column ranges over the values of its type, narrowed by a list of allowed values in its Value.
+// Rows with validity periods only conflict with rows effective at the same time, and gaps are
+// looked for in each period during which the same rows are effective.
+func Analyze(table models.DecisionTable) (*Analysis, error) {
+	policy, err := ParseHitPolicy(table.HitPolicy)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(table.ExpressionLanguage, LanguageNimbus) {
+		return nil, fmt.Errorf("analysis needs FEEL entries, the table uses %s", LanguageNimbus)
+	}
+	a := &analyzer{table: table}
+	if err := a.build(); err != nil {
+		return nil, err
+	}
+	analysis := &Analysis{HitPolicy: policy.String(), Findings: []Finding{}}
+	duplicates := a.duplicates(analysis)
+	for i, r := range a.rows {
+		if r.isEmpty() {
+			analysis.Findings = append(analysis.Findings, Finding{Kind: FindingUnreachable, Rows: []int{i + 1},
+				Message: fmt.Sprintf("row %d can never match: %s", i+1, a.emptyReason(r))})
+		}
+	}
+	switch policy.Name {
+	case Unique:
+		a.overlaps(analysis, duplicates, false)
+	case Any:
+		a.overlaps(analysis, duplicates, true)
+	case First:
+		a.unreachable(analysis, duplicates)
+	}
+	a.gaps(analysis)
+	analysis.Truncated = a.truncated
+	return analysis, nil
+}
+
+// build turns every row into a box within the universe of each column
+func (a *analyzer) build() error {
+	inputs := a.table.InputsColumns
+	cells := make([][]feel.Set, len(inputs))
+	for i, rule := range a.table.Rules {
+		for c, column := range inputs {
+			var entry interface{}
+			if c < len(rule) {
+				entry = rule[c].Value
+			}
+			set, err := cellSet(entry)
+			if err != nil {
+				return CellError{Row: i + 1, Column: column.VarKey, Entry: formatValue(entry), Message: err.Error()}
+			}
+			cells[c] = append(cells[c], set)
+		}
+	}
+	a.universe = make(box, len(inputs))
+	for c, column := range inputs {
+		a.universe[c] = feel.Universe(column.Type, cells[c])
+		if allowed, ok := allowedInputs(column); ok {
+			a.universe[c] = a.universe[c].Intersect(allowed)
+		}
+	}
+	for i, rule := range a.table.Rules {
+		r := make(box, len(inputs))
+		for c := range inputs {
+			r[c] = cells[c][i].Intersect(a.universe[c])
+		}
+		a.rows = append(a.rows, r)
+		outputs := make([]interface{}, len(a.table.OutputsColumns))
+		for o, column := range a.table.OutputsColumns {
+			if idx := len(inputs) + o; idx < len(rule) {
+				outputs[o] = parseOutput(column, rule[idx].Value)
+			}
+		}
+		a.outputs = append(a.outputs, outputs)
+		a.validity = append(a.validity, rowValidity(a.table, i))
+	}
+	return nil
+}
+
+// cellSet returns the values an input entry matches
+func cellSet(entry interface{}) (feel.Set, error) {
+	text, ok := entry.(string)
+	if !ok && entry != nil {
+		return feel.LiteralSet(entry), nil
+	}
+	tests, err := feel.Parse(text)
+	if err != nil {
+		return feel.Set{}, err
+	}
+	return tests.Set(), nil
+}
+
+// allowedInputs reads a list or interval of allowed values, such as the inputValues of an
+// imported DMN table, from the column's Value. A single value is taken as a sample, not a list.
+func allowedInputs(column models.Variables) (feel.Set, bool) {
+	text, ok := column.Value.(string)
+	if !ok || !strings.ContainsAny(text, ",.<>") {
+		return feel.Set{}, false
+	}
+	tests, err := feel.Parse(text)
+	if err != nil {
+		return feel.Set{}, false
+	}
+	return tests.Set(), true
+}
+
+// duplicates reports rows whose inputs equal those of an earlier row and returns them
+func (a *analyzer) duplicates(analysis *Analysis) map[int]bool {
+	duplicate := map[int]bool{}
+	for j := range a.rows {
+		for i := 0; i < j; i++ {
+			if duplicate[i] || !a.validity[i].overlaps(a.validity[j]) || !a.rows[i].equal(a.rows[j]) {
+				continue
+			}
+			duplicate[j] = true
+			message := fmt.Sprintf("rows %d and %d have the same inputs and outputs", i+1, j+1)
+			if !reflect.DeepEqual(a.outputs[i], a.outputs[j]) {
+				message = fmt.Sprintf("rows %d and %d have the same inputs but different outputs", i+1, j+1)
+			}
+			analysis.Findings = append(analysis.Findings, Finding{Kind: FindingDuplicate, Rows: []int{i + 1, j + 1}, Message: message})
+			break
+		}
+	}
+	return duplicate
+}
+
+// overlaps reports pairs of rows some input matches both of. With differentOutputs, as under ANY,
+// only pairs whose outputs differ count.
+func (a *analyzer) overlaps(analysis *Analysis, duplicate map[int]bool, differentOutputs bool) {
+	for i := range a.rows {
+		for j := i + 1; j < len(a.rows); j++ {
+			if duplicate[j] && a.rows[i].equal(a.rows[j]) {
+				continue
+			}
+			if !a.validity[i].overlaps(a.validity[j]) || differentOutputs && reflect.DeepEqual(a.outputs[i], a.outputs[j]) {
+				continue
+			}
+			shared, ok := a.rows[i].intersect(a.rows[j])
+			if !ok {
+				continue
+			}
+			message := fmt.Sprintf("rows %d and %d both match %s", i+1, j+1, a.describe(shared))
+			if differentOutputs {
+				message += " with different outputs"
+			}
+			analysis.Findings = append(analysis.Findings, Finding{Kind: FindingOverlap, Rows: []int{i + 1, j + 1}, Inputs: a.inputs(shared), Message: message})
+		}
+	}
+}
+
+// unreachable reports rows whose every input is already matched by earlier rows effective whenever
+// the row is, which FIRST always picks instead
+func (a *analyzer) unreachable(analysis *Analysis, duplicate map[int]bool) {
+	for j := 1; j < len(a.rows); j++ {
+		if duplicate[j] || a.rows[j].isEmpty() {
+			continue
+		}
+		var covering []box
+		var rows []int
+		for i := 0; i < j; i++ {
+			if !a.validity[i].covers(a.validity[j]) {
+				continue
+			}
+			if _, ok := a.rows[i].intersect(a.rows[j]); ok {
+				covering = append(covering, a.rows[i])
+				rows = append(rows, i+1)
+			}
+		}
+		if len(covering) == 0 {
+			continue
+		}
+		rest, complete := a.subtract(a.rows[j], covering)
+		if !complete || len(rest) > 0 {
+			continue
+		}
+		coveredBy := fmt.Sprintf("row %d matches", rows[0])
+		if len(rows) > 1 {
+			numbers := make([]string, len(rows))
+			for k, number := range rows {
+				numbers[k] = strconv.Itoa(number)
+			}
+			coveredBy = "rows " + strings.Join(numbers, ", ") + " match"
+		}
+		analysis.Findings = append(analysis.Findings, Finding{Kind: FindingUnreachable, Rows: append(rows, j+1),
+			Message: fmt.Sprintf("row %d is unreachable under FIRST: %s all of its inputs first", j+1, coveredBy)})
+	}
+}
+
+// gaps reports the inputs no row matches, in each period of the table's validity bounds
+func (a *analyzer) gaps(analysis *Analysis) {
+	if len(a.universe) == 0 {
+		return
+	}
+	found := 0
+	for _, period := range a.periods() {
+		var effective []box
+		for i, r := range a.rows {
+			if a.validity[i].covers(period) {
+				effective = append(effective, r)
+			}
+		}
+		rest, complete := a.subtract(a.universe, effective)
+		if !complete {
+			return
+		}
+		for _, gap := range rest {
+			if found == maxGapFindings {
+				a.truncated = true
+				return
+			}
+			found++
+			finding := Finding{Kind: FindingGap, Inputs: a.inputs(gap), Message: fmt.Sprintf("no row matches %s", a.describe(gap))}
+			if period.dated() {
+				finding.Period = period.String()
+				finding.Message += " " + finding.Period
+			}
+			analysis.Findings = append(analysis.Findings, finding)
+		}
+	}
+}
+
+// periods splits time at every validity bound of the rows, merging neighbouring periods in which
+// the same rows are effective. A table without dated rows has a single period.
+func (a *analyzer) periods() []validity {
+	var bounds []time.Time
+	for _, v := range a.validity {
+		for _, bound := range []time.Time{v.from, v.to} {
+			if !bound.IsZero() {
+				bounds = append(bounds, bound)
+			}
+		}
+	}
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i].Before(bounds[j]) })
+	var periods []validity
+	var previous string
+	start := time.Time{}
+	for k := 0; k <= len(bounds); k++ {
+		var end time.Time
+		if k < len(bounds) {
+			if end = bounds[k]; end.Equal(start) {
+				continue
+			}
+		}
+		period := validity{from: start, to: end}
+		var rows []string
+		for i, v := range a.validity {
+			if v.covers(period) {
+				rows = append(rows, strconv.Itoa(i))
+			}
+		}
+		if effective := strings.Join(rows, ","); len(periods) > 0 && effective == previous {
+			periods[len(periods)-1].to = end
+		} else {
+			periods, previous = append(periods, period), effective
+		}
+		start = end
+	}
+	return periods
+}
+
+// subtract removes the boxes from region and returns what is left as disjoint boxes. It gives up,
+// returning false, once the pieces grow past maxBoxes.
+func (a *analyzer) subtract(region box, boxes []box) ([]box, bool) {
+	pieces := []box{region}
+	for _, b := range boxes {
+		var next []box
+		for _, piece := range pieces {
+			next = append(next, piece.subtract(b)...)
+		}
+		pieces = next
+		if len(pieces) == 0 {
+			break
+		}
+		if len(pieces) > maxBoxes {
+			a.truncated = true
+			return nil, false
+		}
+	}
+	return pieces, true
+}
+
+func (a *analyzer) inputs(b box) map[string]string {
+	inputs := map[string]string{}
+	for c, column := range a.table.InputsColumns {
+		inputs[column.VarKey] = b[c].Describe(a.universe[c])
+	}
+	return inputs
+}
+
+// describe lists the constrained inputs of a box, such as "data.age < 18 and data.country "IN""
+func (a *analyzer) describe(b box) string {
+	var parts []string
+	for c, column := range a.table.InputsColumns {
+		if text := b[c].Describe(a.universe[c]); text != "-" {
+			parts = append(parts, column.VarKey+" "+text)
+		}
+	}
+	if len(parts) == 0 {
+		return "any input"
+	}
+	return strings.Join(parts, " and ")
+}
+
+func (a *analyzer) emptyReason(b box) string {
+	for c, column := range a.table.InputsColumns {
+		if b[c].IsEmpty() {
+			return fmt.Sprintf("its entry for %s matches no %s value", column.VarKey, orDefault(column.Type, "input"))
+		}
+	}
+	return "no input matches it"
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func (b box) isEmpty() bool {
+	for _, set := range b {
+		if set.IsEmpty() {
+			return true
+		}
+	}
+	return false
+}
+
+func (b box) equal(o box) bool {
+	for c := range b {
+		if !b[c].Equal(o[c]) {
+			return false
+		}
+	}
+	return true
+}
+
+// intersect returns the inputs both boxes match, and whether there are any
+func (b box) intersect(o box) (box, bool) {
+	shared := make(box, len(b))
+	for c := range b {
+		if shared[c] = b[c].Intersect(o[c]); shared[c].IsEmpty() {
+			return nil, false
+		}
+	}
+	return shared, true
+}
+
+// subtract splits the part of b outside o into disjoint boxes: piece k keeps b's values in the
+// columns after k, takes the values outside o in column k and those inside o before it
+func (b box) subtract(o box) []box {
+	if _, ok := b.intersect(o); !ok {
+		return []box{b}
+	}
+	var pieces []box
+	inside := make(box, len(b))
+	copy(inside, b)
+	for k := range b {
+		outside := b[k].Subtract(o[k])
+		if !outside.IsEmpty() {
+			piece := make(box, len(b))
+			copy(piece, inside)
+			piece[k] = outside
+			pieces = append(pieces, piece)
+		}
+		inside[k] = b[k].Intersect(o[k])
+	}
+	return pieces
+}
@@ -0,0 +1,204 @@
+package dtable
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/prithvirajv06/nimbus-uta/go/core/internal/models"
+	"github.com/prithvirajv06/nimbus-uta/go/core/pkg/feel"
+)
+
+// Expression languages of input entries. FEEL is the default; NIMBUS is the ad-hoc syntax of the
+// original engine (a..b ranges, ~regex, ^prefix, $suffix, unquoted comma lists), kept for tables
+// written in it.
+const (
+	LanguageFEEL   = "FEEL"
+	LanguageNimbus = "NIMBUS"
+)
+
+// cellTest is a parsed input entry of a rule row. match reports whether a fact value satisfies it,
+// and unary is the FEEL entry the index reads.
+type cellTest struct {
+	expr  string
+	match func(value interface{}) bool
+	unary *feel.UnaryTests
+}
+
+// anyValue is the test of blank and "-" entries, which match everything
+var anyValue = cellTest{expr: "-", match: func(interface{}) bool { return true }}
+
+// CellError is an input entry that does not parse. Row is the 1-based rule number.
+type CellError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column"`
+	Entry   string `json:"entry"`
+	Message string `json:"message"`
+}
+
+func (e CellError) Error() string {
+	return fmt.Sprintf("row %d, input %s: %s", e.Row, e.Column, e.Message)
+}
+
+// compileCell parses an input entry once, so evaluating a row never re-parses cell strings.
+// Entries saved by the editor are strings, except boolean columns which store true/false.
+func compileCell(language string, entry interface{}) (cellTest, error) {
+	switch v := entry.(type) {
+	case nil:
+		return anyValue, nil
+	case bool:
+		// Only the boolean itself matches, as with the FEEL literal
+		unary, _ := feel.Parse(strconv.FormatBool(v))
+		return cellTest{expr: strconv.FormatBool(v), match: func(value interface{}) bool { return value == v }, unary: unary}, nil
+	case string:
+		if strings.EqualFold(language, LanguageNimbus) {
+			return compileExpression(strings.TrimSpace(v))
+		}
+		tests, err := feel.Parse(v)
+		if err != nil {
+			return cellTest{}, err
+		}
+		return cellTest{expr: strings.TrimSpace(v), match: tests.Match, unary: tests}, nil
+	default:
+		if f, ok := toFloat(v); ok {
+			return cellTest{expr: formatValue(v), match: func(value interface{}) bool {
+				actual, ok := toFloat(value)
+				return ok && actual == f
+			}}, nil
+		}
+		return cellTest{}, fmt.Errorf("unsupported cell entry %v", v)
+	}
+}
+
+// CheckCell reports whether an input entry parses, so editors can reject bad cells on save
+func CheckCell(language string, entry interface{}) error {
+	_, err := compileCell(language, entry)
+	return err
+}
+
+// CheckCells parses every input entry of the table and returns the ones that do not parse, along
+// with rows whose validity period is empty and rule metadata that does not line up with the rows
+func CheckCells(table models.DecisionTable) []CellError {
+	var problems []CellError
+	if err := checkRulesMeta(table); err != nil {
+		problems = append(problems, CellError{Column: "rules_meta", Message: err.Error()})
+	}
+	for i, cells := range table.Rules {
+		if err := checkValidity(table, i); err != nil {
+			problems = append(problems, CellError{Row: i + 1, Column: "valid_to", Entry: FormatDate(rowValidity(table, i).to), Message: err.Error()})
+		}
+		for c, column := range table.InputsColumns {
+			if c >= len(cells) {
+				break
+			}
+			if err := CheckCell(table.ExpressionLanguage, cells[c].Value); err != nil {
+				problems = append(problems, CellError{Row: i + 1, Column: column.VarKey, Entry: formatValue(cells[c].Value), Message: err.Error()})
+			}
+		}
+	}
+	return problems
+}
+
+func compileExpression(expr string) (cellTest, error) {
+	if expr == "" || expr == "-" {
+		return anyValue, nil
+	}
+	switch strings.ToUpper(expr) {
+	case "NULL":
+		return cellTest{expr: expr, match: func(value interface{}) bool { return value == nil }}, nil
+	case "EMPTY":
+		return cellTest{expr: expr, match: func(value interface{}) bool { return value == nil || formatValue(value) == "" }}, nil
+	}
+	if strings.HasPrefix(expr, "!") {
+		expected := unquote(strings.TrimSpace(expr[1:]))
+		return cellTest{expr: expr, match: func(value interface{}) bool { return formatValue(value) != expected }}, nil
+	}
+	if strings.Contains(expr, ",") && !strings.ContainsAny(expr, "[]{}()") {
+		var alternatives []cellTest
+		for _, part := range strings.Split(expr, ",") {
+			alternative, err := compileExpression(strings.TrimSpace(part))
+			if err != nil {
+				return cellTest{}, err
+			}
+			alternatives = append(alternatives, alternative)
+		}
+		return cellTest{expr: expr, match: func(value interface{}) bool {
+			for _, alternative := range alternatives {
+				if alternative.match(value) {
+					return true
+				}
+			}
+			return false
+		}}, nil
+	}
+	if low, high, ok := strings.Cut(expr, ".."); ok {
+		lo, err1 := strconv.ParseFloat(strings.TrimSpace(low), 64)
+		hi, err2 := strconv.ParseFloat(strings.TrimSpace(high), 64)
+		if err1 != nil || err2 != nil {
+			return cellTest{}, fmt.Errorf("invalid range %q", expr)
+		}
+		return cellTest{expr: expr, match: func(value interface{}) bool {
+			actual, ok := toFloat(value)
+			return ok && actual >= lo && actual <= hi
+		}}, nil
+	}
+	for _, op := range []string{">=", "<=", ">", "<"} {
+		if !strings.HasPrefix(expr, op) {
+			continue
+		}
+		bound, err := strconv.ParseFloat(strings.TrimSpace(expr[len(op):]), 64)
+		if err != nil {
+			return cellTest{}, fmt.Errorf("invalid comparison %q", expr)
+		}
+		return cellTest{expr: expr, match: func(value interface{}) bool {
+			actual, ok := toFloat(value)
+			return ok && compareFloat(op, actual, bound)
+		}}, nil
+	}
+	switch expr[0] {
+	case '~':
+		re, err := regexp.Compile(strings.TrimSpace(expr[1:]))
+		if err != nil {
+			return cellTest{}, fmt.Errorf("invalid pattern %q: %w", expr, err)
+		}
+		return cellTest{expr: expr, match: func(value interface{}) bool { return value != nil && re.MatchString(formatValue(value)) }}, nil
+	case '^':
+		prefix := strings.TrimSpace(expr[1:])
+		return cellTest{expr: expr, match: func(value interface{}) bool { return value != nil && strings.HasPrefix(formatValue(value), prefix) }}, nil
+	case '$':
+		suffix := strings.TrimSpace(expr[1:])
+		return cellTest{expr: expr, match: func(value interface{}) bool { return value != nil && strings.HasSuffix(formatValue(value), suffix) }}, nil
+	}
+	expected := unquote(expr)
+	if f, err := strconv.ParseFloat(expected, 64); err == nil && expected == expr {
+		return cellTest{expr: expr, match: func(value interface{}) bool {
+			if actual, ok := toFloat(value); ok {
+				return actual == f
+			}
+			return formatValue(value) == expected
+		}}, nil
+	}
+	return cellTest{expr: expr, match: func(value interface{}) bool { return value != nil && formatValue(value) == expected }}, nil
+}
+
+func compareFloat(op string, a, b float64) bool {
+	switch op {
+	case ">=":
+		return a >= b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	default:
+		return a < b
+	}
+}
+
+// unquote strips one pair of surrounding double quotes
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
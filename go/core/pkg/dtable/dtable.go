@@ -0,0 +1,403 @@
+// Package dtable evaluates decision tables: it parses every cell of a table once and then matches
+// fact documents against the rule rows, combining the matches according to the table's hit policy.
+//
+// This is the part of the engine's dtable package that core needs to check, analyze and test tables
+// on save. Coverage and compilation to pipelines only exist in the engine; a fix to the other files
+// belongs in both copies, which the engine's TestCoreCopies compares.
+package dtable
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prithvirajv06/nimbus-uta/go/core/internal/models"
+)
+
+// Hit policies, with the aggregation of COLLECT in Aggregation
+const (
+	Unique      = "UNIQUE"
+	First       = "FIRST"
+	Priority    = "PRIORITY"
+	Any         = "ANY"
+	Collect     = "COLLECT"
+	RuleOrder   = "RULE ORDER"
+	OutputOrder = "OUTPUT ORDER"
+)
+
+// HitPolicy is a parsed hit policy. Aggregation is SUM, MIN, MAX, COUNT or empty.
+type HitPolicy struct {
+	Name        string
+	Aggregation string
+}
+
+// ParseHitPolicy accepts the DMN names and abbreviations (U, F, C+, ...) as well as the names the
+// editor offers (ALL, SUM, COUNT, ...). An empty policy is UNIQUE, the DMN default.
+func ParseHitPolicy(raw string) (HitPolicy, error) {
+	policy := strings.Join(strings.Fields(strings.ToUpper(strings.ReplaceAll(raw, "_", " "))), " ")
+	switch policy {
+	case "", "U", Unique:
+		return HitPolicy{Name: Unique}, nil
+	case "F", First:
+		return HitPolicy{Name: First}, nil
+	case "P", Priority:
+		return HitPolicy{Name: Priority}, nil
+	case "A", Any:
+		return HitPolicy{Name: Any}, nil
+	case "R", RuleOrder:
+		return HitPolicy{Name: RuleOrder}, nil
+	case "O", OutputOrder:
+		return HitPolicy{Name: OutputOrder}, nil
+	case "C", "ALL", Collect:
+		return HitPolicy{Name: Collect}, nil
+	case "C+", "SUM", "COLLECT SUM":
+		return HitPolicy{Name: Collect, Aggregation: "SUM"}, nil
+	case "C<", "MIN", "COLLECT MIN":
+		return HitPolicy{Name: Collect, Aggregation: "MIN"}, nil
+	case "C>", "MAX", "COLLECT MAX":
+		return HitPolicy{Name: Collect, Aggregation: "MAX"}, nil
+	case "C#", "COUNT", "COLLECT COUNT":
+		return HitPolicy{Name: Collect, Aggregation: "COUNT"}, nil
+	}
+	return HitPolicy{}, fmt.Errorf("unsupported hit policy %q", raw)
+}
+
+func (p HitPolicy) String() string {
+	if p.Aggregation != "" {
+		return p.Name + " " + p.Aggregation
+	}
+	return p.Name
+}
+
+// Table is a decision table compiled for evaluation
+type Table struct {
+	Policy  HitPolicy
+	inputs  []models.Variables
+	outputs []models.Variables
+	rows    []row
+	// Per output column, the allowed values in priority order (PRIORITY, OUTPUT ORDER)
+	priorities [][]interface{}
+	// Narrows the rows to check for large tables; nil when every row is scanned
+	index *index
+}
+
+type row struct {
+	number   int // 1-based, as rule rows are numbered in the editor and in DMN tools
+	tests    []cellTest
+	outputs  []interface{}
+	validity validity
+}
+
+// Result of evaluating one fact document. MatchedRows holds 1-based row numbers.
+type Result struct {
+	Data        map[string]interface{}
+	Outputs     map[string]interface{}
+	MatchedRows []int
+	Trace       []string
+}
+
+// Compile parses the hit policy and every cell of the table. Each row holds the input entries in
+// input column order followed by the output entries; missing input entries match anything. Rule
+// metadata, when present, must hold one entry per row.
+func Compile(table models.DecisionTable) (*Table, error) {
+	policy, err := ParseHitPolicy(table.HitPolicy)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRulesMeta(table); err != nil {
+		return nil, err
+	}
+	compiled := &Table{
+		Policy:  policy,
+		inputs:  table.InputsColumns,
+		outputs: table.OutputsColumns,
+		rows:    make([]row, 0, len(table.Rules)),
+	}
+	for i, cells := range table.Rules {
+		r := row{number: i + 1, tests: make([]cellTest, len(table.InputsColumns)), outputs: make([]interface{}, len(table.OutputsColumns)), validity: rowValidity(table, i)}
+		if err := checkValidity(table, i); err != nil {
+			return nil, CellError{Row: r.number, Column: "valid_to", Entry: FormatDate(r.validity.to), Message: err.Error()}
+		}
+		for c := range table.InputsColumns {
+			r.tests[c] = anyValue
+			if c < len(cells) {
+				if r.tests[c], err = compileCell(table.ExpressionLanguage, cells[c].Value); err != nil {
+					return nil, CellError{Row: r.number, Column: table.InputsColumns[c].VarKey, Entry: formatValue(cells[c].Value), Message: err.Error()}
+				}
+			}
+		}
+		for o, column := range table.OutputsColumns {
+			if idx := len(table.InputsColumns) + o; idx < len(cells) {
+				r.outputs[o] = parseOutput(column, cells[idx].Value)
+			}
+		}
+		compiled.rows = append(compiled.rows, r)
+	}
+	compiled.priorities = make([][]interface{}, len(table.OutputsColumns))
+	for o, column := range table.OutputsColumns {
+		compiled.priorities[o] = allowedValues(column)
+	}
+	compiled.index = buildIndex(table.ExpressionLanguage, table.InputsColumns, compiled.rows)
+	return compiled, nil
+}
+
+// Evaluate evaluates the facts with the rows effective now
+func (t *Table) Evaluate(ctx context.Context, facts map[string]interface{}, trace bool) (*Result, error) {
+	return t.EvaluateAt(ctx, facts, time.Now(), trace)
+}
+
+// EvaluateAt matches the facts against the rows effective at the evaluation date, applies the hit
+// policy and writes the outputs into the facts at the output columns' variable keys. Indexed tables
+// only check the rows the index leaves as candidates. With trace set, every row is checked and
+// Result.Trace explains which rows matched and why the others did not.
+func (t *Table) EvaluateAt(ctx context.Context, facts map[string]interface{}, at time.Time, trace bool) (*Result, error) {
+	if facts == nil {
+		facts = map[string]interface{}{}
+	}
+	result := &Result{Data: facts, Outputs: map[string]interface{}{}, MatchedRows: []int{}}
+	tracef := func(format string, args ...interface{}) {
+		if trace {
+			result.Trace = append(result.Trace, fmt.Sprintf(format, args...))
+		}
+	}
+	values := make([]interface{}, len(t.inputs))
+	found := make([]bool, len(t.inputs))
+	for c, column := range t.inputs {
+		values[c], found[c] = lookup(facts, column.VarKey)
+	}
+
+	rows := t.rows
+	if t.index != nil && !trace {
+		candidates := t.index.candidates(values)
+		rows = make([]row, len(candidates))
+		for i, candidate := range candidates {
+			rows[i] = t.rows[candidate]
+		}
+	}
+	var matched []row
+	for _, r := range rows {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !r.validity.contains(at) {
+			tracef("row %d: not effective on %s, it applies %s", r.number, FormatDate(at), r.validity)
+			continue
+		}
+		if c, ok := t.matchRow(r, values, found); !ok {
+			tracef("row %d: %s = %s does not satisfy %q", r.number, t.inputs[c].VarKey, formatValue(values[c]), r.tests[c].expr)
+			continue
+		}
+		tracef("row %d matched", r.number)
+		matched = append(matched, r)
+		result.MatchedRows = append(result.MatchedRows, r.number)
+		if t.Policy.Name == First {
+			break
+		}
+	}
+
+	tracef("applying hit policy %s to %d matched rows", t.Policy, len(matched))
+	outputs, err := t.applyHitPolicy(matched)
+	if err != nil {
+		tracef("%v", err)
+		return result, err
+	}
+	for o, column := range t.outputs {
+		if value, ok := outputs[o]; ok {
+			result.Outputs[column.VarKey] = value
+			assign(facts, column.VarKey, value)
+			tracef("output %s = %s", column.VarKey, formatValue(value))
+		}
+	}
+	return result, nil
+}
+
+// matchRow reports whether every input entry of the row holds. When it does not, it returns the
+// index of the first failing input column.
+func (t *Table) matchRow(r row, values []interface{}, found []bool) (int, bool) {
+	for c, test := range r.tests {
+		if strings.Contains(t.inputs[c].VarKey, "[*]") {
+			// Array inputs hold when every element satisfies the entry
+			elems, _ := values[c].([]interface{})
+			if !found[c] && test.expr != anyValue.expr {
+				return c, false
+			}
+			for _, elem := range elems {
+				if !test.match(elem) {
+					return c, false
+				}
+			}
+			continue
+		}
+		if !test.match(values[c]) {
+			return c, false
+		}
+	}
+	return -1, true
+}
+
+// applyHitPolicy combines the outputs of the matched rows into one value per output column index.
+// Columns without a value are absent from the map.
+func (t *Table) applyHitPolicy(matched []row) (map[int]interface{}, error) {
+	outputs := map[int]interface{}{}
+	if len(matched) == 0 {
+		if t.Policy.Aggregation == "COUNT" {
+			for o := range t.outputs {
+				outputs[o] = float64(0)
+			}
+		}
+		return outputs, nil
+	}
+	single := func(r row) map[int]interface{} {
+		for o, value := range r.outputs {
+			outputs[o] = value
+		}
+		return outputs
+	}
+	switch t.Policy.Name {
+	case Unique:
+		if len(matched) > 1 {
+			return nil, fmt.Errorf("hit policy UNIQUE violated: rows %s all match", rowNumbers(matched))
+		}
+		return single(matched[0]), nil
+	case First:
+		return single(matched[0]), nil
+	case Any:
+		for _, r := range matched[1:] {
+			if !reflect.DeepEqual(r.outputs, matched[0].outputs) {
+				return nil, fmt.Errorf("hit policy ANY violated: rows %d and %d match with different outputs", matched[0].number, r.number)
+			}
+		}
+		return single(matched[0]), nil
+	case Priority:
+		best := matched[0]
+		for _, r := range matched[1:] {
+			if t.higherPriority(r, best) {
+				best = r
+			}
+		}
+		return single(best), nil
+	case OutputOrder:
+		sort.SliceStable(matched, func(i, j int) bool { return t.higherPriority(matched[i], matched[j]) })
+	}
+
+	// COLLECT, RULE ORDER and OUTPUT ORDER return, per output column, the list of matched values
+	for o := range t.outputs {
+		var list []interface{}
+		for _, r := range matched {
+			if r.outputs[o] != nil {
+				list = append(list, r.outputs[o])
+			}
+		}
+		if t.Policy.Aggregation == "" {
+			if list == nil {
+				list = []interface{}{}
+			}
+			outputs[o] = list
+			continue
+		}
+		value, err := aggregate(t.Policy.Aggregation, list)
+		if err != nil {
+			return nil, fmt.Errorf("output %s: %w", t.outputs[o].VarKey, err)
+		}
+		if value != nil {
+			outputs[o] = value
+		}
+	}
+	return outputs, nil
+}
+
+func aggregate(aggregation string, list []interface{}) (interface{}, error) {
+	if aggregation == "COUNT" {
+		return float64(len(list)), nil
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+	var acc float64
+	switch aggregation {
+	case "MIN":
+		acc = math.Inf(1)
+	case "MAX":
+		acc = math.Inf(-1)
+	}
+	for _, value := range list {
+		f, ok := toFloat(value)
+		if !ok {
+			return nil, fmt.Errorf("cannot aggregate non-numeric value %q with %s", formatValue(value), aggregation)
+		}
+		switch aggregation {
+		case "SUM":
+			acc += f
+		case "MIN":
+			acc = math.Min(acc, f)
+		case "MAX":
+			acc = math.Max(acc, f)
+		}
+	}
+	return acc, nil
+}
+
+// higherPriority orders rows by the position of their outputs in the output columns' allowed
+// values, earlier meaning higher, comparing column by column. Tables without allowed values fall
+// back to a numeric __priority__ output column, where larger wins, and then to rule order.
+func (t *Table) higherPriority(a, b row) bool {
+	for o, column := range t.outputs {
+		if column.VarKey == "__priority__" {
+			pa, _ := toFloat(a.outputs[o])
+			pb, _ := toFloat(b.outputs[o])
+			if pa != pb {
+				return pa > pb
+			}
+			continue
+		}
+		if len(t.priorities[o]) == 0 {
+			continue
+		}
+		ra, rb := rank(t.priorities[o], a.outputs[o]), rank(t.priorities[o], b.outputs[o])
+		if ra != rb {
+			return ra < rb
+		}
+	}
+	return a.number < b.number
+}
+
+func rank(allowed []interface{}, value interface{}) int {
+	for i, candidate := range allowed {
+		if formatValue(candidate) == formatValue(value) {
+			return i
+		}
+	}
+	return len(allowed)
+}
+
+// allowedValues reads the output column's allowed values, declared in priority order in its
+// value as a list or as a comma separated string such as "HIGH","MEDIUM","LOW"
+func allowedValues(column models.Variables) []interface{} {
+	switch v := column.Value.(type) {
+	case []interface{}:
+		return v
+	case string:
+		if !strings.Contains(v, ",") || strings.HasPrefix(v, "[") || strings.HasPrefix(v, "{") {
+			return nil
+		}
+		var allowed []interface{}
+		for _, part := range strings.Split(v, ",") {
+			allowed = append(allowed, parseOutput(column, part))
+		}
+		return allowed
+	}
+	return nil
+}
+
+func rowNumbers(rows []row) string {
+	numbers := make([]string, len(rows))
+	for i, r := range rows {
+		numbers[i] = strconv.Itoa(r.number)
+	}
+	return strings.Join(numbers, ", ")
+}
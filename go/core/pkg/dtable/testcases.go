@@ -0,0 +1,121 @@
+package dtable
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prithvirajv06/nimbus-uta/go/core/internal/models"
+)
+
+// TestResult is the outcome of one test case. Mismatches explains each expected variable whose
+// value differed.
+type TestResult struct {
+	Name        string                 `json:"name"`
+	Passed      bool                   `json:"passed"`
+	MatchedRows []int                  `json:"matched_rows"`
+	Outputs     map[string]interface{} `json:"outputs,omitempty"`
+	Error       string                 `json:"error,omitempty"`
+	Mismatches  []string               `json:"mismatches,omitempty"`
+}
+
+// TestReport is the result of RunTests
+type TestReport struct {
+	Passed  int          `json:"passed"`
+	Failed  int          `json:"failed"`
+	Results []TestResult `json:"results"`
+}
+
+// RunTests evaluates the table on the input of each of its test cases and compares the outcome
+// with what the case expects. Inputs are copied, so the table's test cases are left untouched.
+// Tables that require other tables are tested alone: their inputs must carry the outputs of the
+// required tables. It fails when the table does not compile.
+func RunTests(ctx context.Context, table models.DecisionTable) (*TestReport, error) {
+	compiled, err := Compile(table)
+	if err != nil {
+		return nil, err
+	}
+	report := &TestReport{Results: []TestResult{}}
+	for i, testCase := range table.TestCases {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result := runTest(ctx, compiled, testCase)
+		if result.Name == "" {
+			result.Name = "case " + strconv.Itoa(i+1)
+		}
+		if result.Passed {
+			report.Passed++
+		} else {
+			report.Failed++
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+func runTest(ctx context.Context, t *Table, testCase models.DecisionTestCase) TestResult {
+	result := TestResult{Name: testCase.Name, MatchedRows: []int{}}
+	facts, _ := normalize(testCase.Input).(map[string]interface{})
+	at := time.Now()
+	if testCase.EvaluationDate != nil {
+		at = *testCase.EvaluationDate
+	}
+	evaluated, err := t.EvaluateAt(ctx, facts, at, false)
+	if evaluated != nil {
+		result.MatchedRows = evaluated.MatchedRows
+		result.Outputs = evaluated.Outputs
+	}
+	if testCase.ExpectedError != "" {
+		switch {
+		case err == nil:
+			result.Mismatches = append(result.Mismatches, fmt.Sprintf("expected an error containing %q, evaluation succeeded", testCase.ExpectedError))
+		case !strings.Contains(err.Error(), testCase.ExpectedError):
+			result.Error = err.Error()
+			result.Mismatches = append(result.Mismatches, fmt.Sprintf("expected an error containing %q", testCase.ExpectedError))
+		}
+		result.Passed = len(result.Mismatches) == 0
+		return result
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	keys := make([]string, 0, len(testCase.Expected))
+	for key := range testCase.Expected {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		want := normalize(testCase.Expected[key])
+		got, found := lookup(evaluated.Data, key)
+		switch {
+		case !found && want == nil:
+		case !found:
+			result.Mismatches = append(result.Mismatches, fmt.Sprintf("%s: expected %s, got nothing", key, formatValue(want)))
+		case !reflect.DeepEqual(normalize(got), want):
+			result.Mismatches = append(result.Mismatches, fmt.Sprintf("%s: expected %s, got %s", key, formatValue(want), formatValue(got)))
+		}
+	}
+	result.Passed = len(result.Mismatches) == 0
+	return result
+}
+
+// normalize copies a value through JSON, so that values read from requests and from the database
+// compare equal whatever numeric types they were decoded into
+func normalize(value interface{}) interface{} {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var out interface{}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return value
+	}
+	return out
+}
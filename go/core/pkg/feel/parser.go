@@ -0,0 +1,246 @@
+package feel
+
+import (
+	"fmt"
+	"strconv"
+)
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isPunct(text string) bool {
+	t := p.peek()
+	return t.kind == tokenPunct && t.text == text
+}
+
+func (p *parser) isName(text string) bool {
+	t := p.peek()
+	return t.kind == tokenName && t.text == text
+}
+
+func (p *parser) expect(text string) error {
+	if !p.isPunct(text) {
+		return p.errorf("expected %s", text)
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	t := p.peek()
+	message := fmt.Sprintf(format, args...)
+	if t.kind == tokenEOF {
+		message += " but the entry ended"
+	} else {
+		message += fmt.Sprintf(" but found %s", describe(t))
+	}
+	return &SyntaxError{Text: p.text, Offset: t.offset, Message: message}
+}
+
+func describe(t token) string {
+	if t.kind == tokenString {
+		return strconv.Quote(t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// parseEntry reads "-", not(...) or a list of positive unary tests, up to the end of the entry
+func (p *parser) parseEntry() (*UnaryTests, error) {
+	entry := &UnaryTests{Text: p.text}
+	if p.peek().kind == tokenEOF {
+		entry.any = true
+		return entry, nil
+	}
+	if p.isPunct("-") && p.tokens[p.pos+1].kind == tokenEOF {
+		entry.any = true
+		return entry, nil
+	}
+	if p.isName("not") && p.tokens[p.pos+1].kind == tokenPunct && p.tokens[p.pos+1].text == "(" {
+		p.pos += 2
+		entry.negated = true
+	}
+	tests, err := p.parseTests()
+	if err != nil {
+		return nil, err
+	}
+	entry.tests = tests
+	if entry.negated {
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+	if p.peek().kind != tokenEOF {
+		return nil, p.errorf("expected , or the end of the entry")
+	}
+	return entry, nil
+}
+
+func (p *parser) parseTests() ([]test, error) {
+	var tests []test
+	for {
+		t, err := p.parseTest()
+		if err != nil {
+			return nil, err
+		}
+		tests = append(tests, t)
+		if !p.isPunct(",") {
+			return tests, nil
+		}
+		p.next()
+	}
+}
+
+// parseTest reads null, a comparison, an interval or a literal tested for equality
+func (p *parser) parseTest() (test, error) {
+	if p.isName("null") {
+		p.next()
+		return test{eval: func(value interface{}) *bool { return boolean(value == nil) }}, nil
+	}
+	t := p.peek()
+	if t.kind == tokenPunct {
+		switch t.text {
+		case "<", "<=", ">", ">=", "=", "!=":
+			p.next()
+			limitToken := p.peek()
+			limit, err := p.parseEndpoint()
+			if err != nil {
+				return test{}, err
+			}
+			if _, isBool := limit.(bool); isBool && t.text != "=" && t.text != "!=" {
+				return test{}, &SyntaxError{Text: p.text, Offset: limitToken.offset, Message: "booleans cannot be compared with " + t.text}
+			}
+			return comparison(t.text, limit), nil
+		case "[", "(", "]":
+			return p.parseInterval()
+		}
+	}
+	literal, err := p.parseEndpoint()
+	if err != nil {
+		return test{}, err
+	}
+	return comparison("=", literal), nil
+}
+
+// parseInterval reads [a..b] where [ or ] on either side marks a closed or open bound as in
+// FEEL, and ( and ) are the alternative open bounds
+func (p *parser) parseInterval() (test, error) {
+	open := p.next()
+	low, err := p.parseEndpoint()
+	if err != nil {
+		return test{}, err
+	}
+	if err := p.expect(".."); err != nil {
+		return test{}, err
+	}
+	high, err := p.parseEndpoint()
+	if err != nil {
+		return test{}, err
+	}
+	if !p.isPunct("]") && !p.isPunct(")") && !p.isPunct("[") {
+		return test{}, p.errorf("expected ], ) or [ to close the interval")
+	}
+	closing := p.next().text
+	_, lowBool := low.(bool)
+	_, highBool := high.(bool)
+	if lowBool || highBool {
+		return test{}, &SyntaxError{Text: p.text, Offset: open.offset, Message: "booleans cannot bound an interval"}
+	}
+	lowOp, highOp := ">=", "<="
+	if open.text != "[" {
+		lowOp = ">"
+	}
+	if closing != "]" {
+		highOp = "<"
+	}
+	above, below := comparison(lowOp, low), comparison(highOp, high)
+	between := test{eval: func(value interface{}) *bool {
+		return conjunction(above.eval(value), below.eval(value))
+	}}
+	// An interval between literals of different domains matches nothing
+	if above.domain == below.domain {
+		between.domain = above.domain
+		between.ranges = intersect(above.ranges, below.ranges)
+	}
+	return between, nil
+}
+
+// parseEndpoint reads a literal: a number, possibly negative, a string, a boolean or a temporal literal
+func (p *parser) parseEndpoint() (interface{}, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokenNumber:
+		p.next()
+		return strconv.ParseFloat(t.text, 64)
+	case tokenString:
+		p.next()
+		return t.text, nil
+	case tokenPunct:
+		switch t.text {
+		case "-":
+			if p.tokens[p.pos+1].kind == tokenNumber {
+				p.next()
+				n, err := strconv.ParseFloat(p.next().text, 64)
+				return -n, err
+			}
+		case "@":
+			p.next()
+			if p.peek().kind != tokenString {
+				return nil, p.errorf("expected a string after @")
+			}
+			literal := p.next()
+			value, ok := parseTemporal("", literal.text)
+			if !ok {
+				return nil, &SyntaxError{Text: p.text, Offset: literal.offset, Message: fmt.Sprintf("%q is not a date, time or date and time", literal.text)}
+			}
+			return value, nil
+		}
+	case tokenName:
+		switch t.text {
+		case "true", "false":
+			p.next()
+			return t.text == "true", nil
+		case "date", "time":
+			return p.parseTemporalCall()
+		}
+		return nil, &SyntaxError{Text: p.text, Offset: t.offset,
+			Message: fmt.Sprintf("unknown name %q; names are not supported, write strings in quotes like %q", t.text, t.text)}
+	}
+	return nil, p.errorf("expected a literal")
+}
+
+// parseTemporalCall reads date("..."), time("...") or date and time("...")
+func (p *parser) parseTemporalCall() (interface{}, error) {
+	kind := p.next().text
+	if kind == "date" && p.isName("and") {
+		p.next()
+		if !p.isName("time") {
+			return nil, p.errorf("expected time after date and")
+		}
+		p.next()
+		kind = kindDateTime
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenString {
+		return nil, p.errorf("expected a string argument to %s", kind)
+	}
+	literal := p.next()
+	value, ok := parseTemporal(kind, literal.text)
+	if !ok {
+		return nil, &SyntaxError{Text: p.text, Offset: literal.offset, Message: fmt.Sprintf("%q is not a valid %s", literal.text, kind)}
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,168 @@
+package feel
+
+import (
+	"strings"
+	"time"
+)
+
+// Kinds of temporal values
+const (
+	kindDate     = "date"
+	kindTime     = "time"
+	kindDateTime = "date and time"
+)
+
+// temporal is a date, a time of day or a date and time
+type temporal struct {
+	kind  string
+	value time.Time
+}
+
+var temporalLayouts = map[string][]string{
+	kindDate:     {"2006-01-02"},
+	kindTime:     {"15:04:05Z07:00", "15:04:05.999999999Z07:00", "15:04:05", "15:04:05.999999999", "15:04"},
+	kindDateTime: {time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02T15:04:05", "2006-01-02T15:04"},
+}
+
+// parseTemporal parses text as the given kind, or as whichever kind fits when kind is empty.
+// Values without a zone are read as UTC.
+func parseTemporal(kind, text string) (temporal, bool) {
+	kinds := []string{kind}
+	if kind == "" {
+		kinds = []string{kindDateTime, kindDate, kindTime}
+	}
+	for _, k := range kinds {
+		for _, layout := range temporalLayouts[k] {
+			if t, err := time.Parse(layout, text); err == nil {
+				return temporal{kind: k, value: t}, true
+			}
+		}
+	}
+	return temporal{}, false
+}
+
+// comparison builds the test value op literal
+func comparison(op string, literal interface{}) test {
+	return test{eval: compareWith(op, literal), domain: domainOf(literal), ranges: rangesFor(op, literal)}
+}
+
+func compareWith(op string, literal interface{}) func(value interface{}) *bool {
+	return func(value interface{}) *bool {
+		if value == nil {
+			if op == "=" || op == "!=" {
+				// FEEL equality is defined for null: null = x is false unless x is null too
+				return boolean(op == "!=")
+			}
+			return nil
+		}
+		order, comparable := compare(value, literal)
+		if !comparable {
+			return nil
+		}
+		switch op {
+		case "=":
+			return boolean(order == 0)
+		case "!=":
+			return boolean(order != 0)
+		case "<":
+			return boolean(order < 0)
+		case "<=":
+			return boolean(order <= 0)
+		case ">":
+			return boolean(order > 0)
+		default:
+			return boolean(order >= 0)
+		}
+	}
+}
+
+// compare orders a fact value against a literal of the same type; comparable is false when the
+// types differ. Booleans are only equal or not, so the parser rejects ordering them.
+func compare(value, literal interface{}) (int, bool) {
+	switch l := literal.(type) {
+	case float64:
+		v, ok := toNumber(value)
+		if !ok {
+			return 0, false
+		}
+		return order(v < l, v > l), true
+	case string:
+		v, ok := value.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(v, l), true
+	case bool:
+		v, ok := value.(bool)
+		if !ok {
+			return 0, false
+		}
+		if v == l {
+			return 0, true
+		}
+		return 1, true
+	case temporal:
+		v, ok := toTemporal(value, l.kind)
+		if !ok {
+			return 0, false
+		}
+		return order(v.Before(l.value), v.After(l.value)), true
+	}
+	return 0, false
+}
+
+func order(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	}
+	return 0
+}
+
+func toNumber(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// toTemporal reads a fact as a temporal value of kind
+func toTemporal(value interface{}, kind string) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		switch kind {
+		case kindDate:
+			return time.Date(v.Year(), v.Month(), v.Day(), 0, 0, 0, 0, time.UTC), true
+		case kindTime:
+			return time.Date(0, 1, 1, v.Hour(), v.Minute(), v.Second(), v.Nanosecond(), v.Location()), true
+		}
+		return v, true
+	case string:
+		if t, ok := parseTemporal(kind, v); ok {
+			return t.value, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// conjunction is FEEL's and: false if either side is false, else null if either is null
+func conjunction(a, b *bool) *bool {
+	if (a != nil && !*a) || (b != nil && !*b) {
+		return boolean(false)
+	}
+	if a == nil || b == nil {
+		return nil
+	}
+	return boolean(true)
+}
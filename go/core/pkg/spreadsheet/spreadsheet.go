@@ -0,0 +1,118 @@
+// Package spreadsheet reads and writes the cells of a single sheet as CSV or XLSX
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Supported formats
+const (
+	CSV  = "csv"
+	XLSX = "xlsx"
+)
+
+// Format picks the format from an explicit name, falling back to the file extension and then CSV
+func Format(requested, filename string) (string, error) {
+	format := strings.ToLower(requested)
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), ".")
+	}
+	switch format {
+	case "", CSV:
+		return CSV, nil
+	case XLSX:
+		return XLSX, nil
+	}
+	return "", fmt.Errorf("unsupported spreadsheet format %q, expected csv or xlsx", format)
+}
+
+// ContentType returns the MIME type of a format
+func ContentType(format string) string {
+	if format == XLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv"
+}
+
+// Read returns the rows of a CSV file or of the first sheet of an XLSX workbook. Rows may be
+// shorter than the widest row, as trailing blank cells are not stored.
+func Read(format string, r io.Reader) ([][]string, error) {
+	if format == XLSX {
+		book, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid XLSX file: %w", err)
+		}
+		defer book.Close()
+		return book.GetRows(book.GetSheetName(0))
+	}
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV file: %w", err)
+	}
+	return rows, nil
+}
+
+// Write writes rows as a CSV file or as an XLSX workbook holding one sheet named after sheet
+func Write(format string, w io.Writer, sheet string, rows [][]string) error {
+	if format != XLSX {
+		writer := csv.NewWriter(w)
+		if err := writer.WriteAll(rows); err != nil {
+			return err
+		}
+		return writer.Error()
+	}
+	book := excelize.NewFile()
+	defer book.Close()
+	name := sheetName(sheet)
+	if err := book.SetSheetName(book.GetSheetName(0), name); err != nil {
+		return err
+	}
+	for r, row := range rows {
+		for c, value := range row {
+			cell, err := excelize.CoordinatesToCellName(c+1, r+1)
+			if err != nil {
+				return err
+			}
+			// Cells are written as text so entries such as 0010 or >=5 are not reinterpreted
+			if err := book.SetCellStr(name, cell, value); err != nil {
+				return err
+			}
+		}
+	}
+	return book.Write(w)
+}
+
+// ColumnName turns a zero based column index into its spreadsheet letters, A, B, ..., AA
+func ColumnName(index int) string {
+	name, err := excelize.ColumnNumberToName(index + 1)
+	if err != nil {
+		return fmt.Sprint(index + 1)
+	}
+	return name
+}
+
+// sheetName drops the characters Excel forbids in sheet names and caps the length at 31
+func sheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`\/?*[]:`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	if len([]rune(name)) > 31 {
+		name = string([]rune(name)[:31])
+	}
+	if name == "" {
+		return "Sheet1"
+	}
+	return name
+}
@@ -28,7 +28,7 @@ func GenerateScript(wfDef models.WorkflowDef) string {
 
 	// 2. Start Workflow Execution
 	buffer.WriteString("\n// --- Workflow Execution ---\n")
-	buffer.WriteString("var data = {};\n") // Root data object
+	buffer.WriteString("var data = data || {};\n") // Root data object, bound to the request facts
 	buffer.WriteString("var log = [];\n")
 	//3. Initialize Metadata Variables
 	if len(wfDef.Metadata) > 0 {
@@ -55,6 +55,8 @@ func compileSteps(steps []models.PipelineStep, indent int, isRoot bool) string {
 			assignmentStep(&out, step, pad, isRoot)
 		case "push_array":
 			pushArrayStep(&out, step, pad, isRoot)
+		case "remove":
+			removeStep(&out, step, pad, isRoot)
 		case "condition":
 			conditionStep(&out, step, pad, indent, isRoot)
 		case "network_call":
@@ -75,19 +77,34 @@ func assignmentStep(out *bytes.Buffer, step models.PipelineStep, pad string, isR
 		step.Target = "data." + step.Target
 	}
 	valStr := string(step.Value)
+	if step.Statement != "" {
+		// An expression computing the value, such as data.Total + data.Tax
+		valStr = step.Statement
+	}
 	//addLog(information about assignment)
 	out.WriteString(fmt.Sprintf("\n%s addLog('Assigning %s to %s');\n", pad, valStr, step.Target))
 	out.WriteString(fmt.Sprintf("%s %s = %s;", pad, step.Target, valStr))
 }
 
 func pushArrayStep(out *bytes.Buffer, step models.PipelineStep, pad string, isRoot bool) {
-	// Generate: $push(data, "Transactions", {...});
+	// Generate: (function (array) { array.push(...); data.Transactions = array; })(data.Transactions == null ? [] : data.Transactions);
+	// Facts arrays are Go slices the runtime copies on every read, so the array read is written
+	// back; a missing array starts empty
 	if isRoot {
 		step.Target = "data." + step.Target
 	}
 	valStr := string(step.Value)
 	out.WriteString(fmt.Sprintf("\n%s addLog('Pushing %s to %s');\n", pad, valStr, step.Target))
-	out.WriteString(fmt.Sprintf("%s %s.push(JSON.parse(%s));", pad, step.Target, valStr))
+	out.WriteString(fmt.Sprintf("%s (function (array) { array.push(JSON.parse(%s)); %s = array; })(%s == null ? [] : %s);", pad, valStr, step.Target, step.Target, step.Target))
+}
+
+func removeStep(out *bytes.Buffer, step models.PipelineStep, pad string, isRoot bool) {
+	// Generate: delete data.Discount;
+	if isRoot {
+		step.Target = "data." + step.Target
+	}
+	out.WriteString(fmt.Sprintf("\n%s addLog('Removing %s');\n", pad, step.Target))
+	out.WriteString(fmt.Sprintf("%s delete %s;", pad, step.Target))
 }
 
 func conditionStep(out *bytes.Buffer, step models.PipelineStep, pad string, indent int, isRoot bool) {
@@ -126,7 +143,7 @@ func networkCallStep(out *bytes.Buffer, step models.PipelineStep, pad string, is
 
 func forEachStep(out *bytes.Buffer, step models.PipelineStep, pad string, indent int, isRoot bool) {
 	indexVar := fmt.Sprintf("i%d", indent)
-	var arrayPath string
+	arrayPath := step.Target
 	if isRoot {
 		arrayPath = "data." + step.Target
 	}
@@ -136,6 +153,8 @@ func forEachStep(out *bytes.Buffer, step models.PipelineStep, pad string, indent
 	if contextVar != "" {
 		out.WriteString(fmt.Sprintf("\n%s  var %s = %s[%s];", pad, contextVar, arrayPath, indexVar))
 
+		// A copy, so that compiling the pipeline again does not prefix the targets twice
+		step.Children = append([]models.PipelineStep(nil), step.Children...)
 		for index, childStep := range step.Children {
 			step.Children[index].Target = contextVar + "." + childStep.Target
 		}
@@ -0,0 +1,140 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+)
+
+// TestPushArray runs push_array pipelines through the runtime. Facts arrays are Go slices the
+// runtime copies on every read, so pushes only last when the script writes the array back.
+func TestPushArray(t *testing.T) {
+	for _, c := range []scriptCase{
+		{
+			name:     "onto a facts array",
+			facts:    `{"items": [{"id": 0}]}`,
+			pipeline: `[{"type": "push_array", "target": "items", "value": "{\"id\":1}"}]`,
+			want:     `{"items": [{"id": 0}, {"id": 1}]}`,
+		},
+		{
+			name:  "twice",
+			facts: `{"items": []}`,
+			pipeline: `[{"type": "push_array", "target": "items", "value": "1"},
+				{"type": "push_array", "target": "items", "value": "[2]"}]`,
+			want: `{"items": [1, [2]]}`,
+		},
+		{
+			name:     "onto a missing target",
+			facts:    `{}`,
+			pipeline: `[{"type": "push_array", "target": "items", "value": "\"a\""}]`,
+			want:     `{"items": ["a"]}`,
+		},
+		{
+			name:     "onto a null target",
+			facts:    `{"items": null}`,
+			pipeline: `[{"type": "push_array", "target": "items", "value": "\"a\""}]`,
+			want:     `{"items": ["a"]}`,
+		},
+		{
+			name:  "onto a nested array in a condition",
+			facts: `{"order": {"total": 60, "lines": ["x"]}}`,
+			pipeline: `[{"type": "condition", "statement": "true", "children": [
+				{"type": "condition", "statement": "data.order.total > 50", "target": "scope", "children": [
+					{"type": "push_array", "target": "data.order.lines", "value": "\"y\""},
+					{"type": "push_array", "target": "data.order.flags", "value": "\"large\""}]}]}]`,
+			want: `{"order": {"total": 60, "lines": ["x", "y"], "flags": ["large"]}}`,
+		},
+		{
+			name:     "onto text fails",
+			facts:    `{"items": "x"}`,
+			pipeline: `[{"type": "push_array", "target": "items", "value": "1"}]`,
+			wantErr:  true,
+		},
+	} {
+		t.Run(c.name, c.run)
+	}
+}
+
+// TestSteps runs assignments of expressions, removals and nested loops through the runtime
+func TestSteps(t *testing.T) {
+	for _, c := range []scriptCase{
+		{
+			name:     "assigning an expression",
+			facts:    `{"total": 10, "tax": 2}`,
+			pipeline: `[{"type": "assignment", "target": "gross", "statement": "data.total + data.tax"}]`,
+			want:     `{"total": 10, "tax": 2, "gross": 12}`,
+		},
+		{
+			name:     "removing a member",
+			facts:    `{"order": {"id": 1, "discount": 5}}`,
+			pipeline: `[{"type": "remove", "target": "order.discount"}]`,
+			want:     `{"order": {"id": 1}}`,
+		},
+		{
+			name:  "looping over nested arrays",
+			facts: `{"orders": [{"lines": [{"qty": 1}, {"qty": 2}]}, {"lines": [{"qty": 3}]}]}`,
+			pipeline: `[{"type": "condition", "statement": "true", "target": "scope", "children": [
+				{"type": "for_each", "target": "data.orders", "context_var": "order", "children": [
+					{"type": "for_each", "target": "lines", "context_var": "line", "children": [
+						{"type": "assignment", "target": "qty", "statement": "line.qty * 10"}]}]}]}]`,
+			want: `{"orders": [{"lines": [{"qty": 10}, {"qty": 20}]}, {"lines": [{"qty": 30}]}]}`,
+		},
+	} {
+		t.Run(c.name, c.run)
+	}
+}
+
+// scriptCase compiles pipeline, runs it on facts and expects the facts want, or an error when
+// wantErr is set
+type scriptCase struct {
+	name     string
+	facts    string
+	pipeline string
+	want     string
+	wantErr  bool
+}
+
+func (c scriptCase) run(t *testing.T) {
+	var facts map[string]interface{}
+	if err := json.Unmarshal([]byte(c.facts), &facts); err != nil {
+		t.Fatalf("bad facts: %v", err)
+	}
+	var steps []models.PipelineStep
+	if err := json.Unmarshal([]byte(c.pipeline), &steps); err != nil {
+		t.Fatalf("bad pipeline: %v", err)
+	}
+	got, _, err := ExecuteContext(context.Background(), GenerateScript(models.WorkflowDef{Pipeline: steps}), facts)
+	if c.wantErr {
+		if err == nil {
+			t.Fatalf("expected an error, the script left %v", got)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want map[string]interface{}
+	if err := json.Unmarshal([]byte(c.want), &want); err != nil {
+		t.Fatalf("bad want: %v", err)
+	}
+	if !reflect.DeepEqual(normalize(t, got), want) {
+		t.Errorf("the script left %v, expected %v", got, want)
+	}
+}
+
+// normalize reads the facts a script leaves the way they arrived, through JSON
+func normalize(t *testing.T, facts map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	raw, err := json.Marshal(facts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
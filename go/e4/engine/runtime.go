@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -9,6 +10,12 @@ import (
 
 // Execute runs the compiled script against the input data
 func Execute(script string, inputData map[string]interface{}) (map[string]interface{}, []string, error) {
+	return ExecuteContext(context.Background(), script, inputData)
+}
+
+// ExecuteContext runs the compiled script like Execute, and also interrupts it when ctx is done,
+// so caller deadlines (e.g. gRPC) are propagated into the VM.
+func ExecuteContext(ctx context.Context, script string, inputData map[string]interface{}) (map[string]interface{}, []string, error) {
 	vm := goja.New()
 
 	// 1. Setup Input Data
@@ -26,9 +33,14 @@ func Execute(script string, inputData map[string]interface{}) (map[string]interf
 	})
 
 	// 3. Run with Timeout
-	time.AfterFunc(200*time.Millisecond, func() {
+	timer := time.AfterFunc(200*time.Millisecond, func() {
 		vm.Interrupt("timeout")
 	})
+	defer timer.Stop()
+	stop := context.AfterFunc(ctx, func() {
+		vm.Interrupt(ctx.Err())
+	})
+	defer stop()
 
 	_, err := vm.RunString(script)
 	if err != nil {
@@ -37,6 +49,28 @@ func Execute(script string, inputData map[string]interface{}) (map[string]interf
 
 	// 4. Extract Modified Data
 	res := vm.Get("data")
-	logs := vm.Get("log")
-	return res.Export().(map[string]interface{}), logs.Export().([]string), nil
+	return res.Export().(map[string]interface{}), exportLogs(vm.Get("log")), nil
+}
+
+// exportLogs flattens the script's log entries ({timestamp, message}) into plain messages
+func exportLogs(logValue goja.Value) []string {
+	var logs []string
+	entries, ok := logValue.Export().([]interface{})
+	if !ok {
+		return logs
+	}
+	for _, entry := range entries {
+		if e, ok := entry.(map[string]interface{}); ok {
+			logs = append(logs, fmt.Sprintf("%v %v", e["timestamp"], e["message"]))
+			continue
+		}
+		logs = append(logs, fmt.Sprintf("%v", entry))
+	}
+	return logs
+}
+
+// Validate compiles the script without running it and reports syntax errors
+func Validate(script string) error {
+	_, err := goja.Compile("", script, false)
+	return err
 }
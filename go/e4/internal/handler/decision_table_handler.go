@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/service"
+)
+
+type DecisionTableHandler struct {
+	service *service.DecisionTableService
+}
+
+func NewDecisionTableHandler(svc *service.DecisionTableService) *DecisionTableHandler {
+	return &DecisionTableHandler{
+		service: svc,
+	}
+}
+
+func (h *DecisionTableHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.POST("/decision-table/invoke", h.service.HandleDecisionTableExecution)
+	router.POST("/decision-table/invoke/graph", h.service.HandleDecisionGraphExecution)
+	router.POST("/decision-table/compile", h.service.CompileDecisionTable)
+	router.POST("/decision-table/coverage", h.service.HandleDecisionTableCoverage)
+	router.POST("/decision-table/coverage/history", h.service.HandleDecisionTableHistoryCoverage)
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/service"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/proto/enginepb"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ExecutionGRPCHandler serves engine invocation over gRPC, next to the REST ExecutionHandler
+type ExecutionGRPCHandler struct {
+	enginepb.UnimplementedEngineServiceServer
+	service *service.ExecutionService
+}
+
+func NewExecutionGRPCHandler(svc *service.ExecutionService) *ExecutionGRPCHandler {
+	return &ExecutionGRPCHandler{
+		service: svc,
+	}
+}
+
+func (h *ExecutionGRPCHandler) RegisterServer(server *grpc.Server) {
+	enginepb.RegisterEngineServiceServer(server, h)
+}
+
+func (h *ExecutionGRPCHandler) Invoke(ctx context.Context, req *enginepb.InvokeRequest) (*enginepb.InvokeResponse, error) {
+	result, err := h.service.Invoke(ctx, grpcMeta(ctx), req.GetNimbId(), service.VersionRef(int(req.GetVersion()), req.GetAlias()), req.GetFacts().AsMap())
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	data, err := structpb.NewStruct(result.Data)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to encode engine output: %v", err)
+	}
+	resp := &enginepb.InvokeResponse{Data: data, TimeTakenMs: result.Duration.Milliseconds(), ExecutionId: result.ExecutionID, Version: int32(result.Version)}
+	if req.GetDebug() {
+		resp.Logs = result.Logs
+	}
+	return resp, nil
+}
+
+func (h *ExecutionGRPCHandler) InvokeBatch(req *enginepb.InvokeBatchRequest, stream enginepb.EngineService_InvokeBatchServer) error {
+	facts := make([]map[string]interface{}, len(req.GetFacts()))
+	for i, doc := range req.GetFacts() {
+		facts[i] = doc.AsMap()
+	}
+	var sendErr error
+	err := h.service.InvokeBatch(stream.Context(), req.GetNimbId(), service.VersionRef(int(req.GetVersion()), req.GetAlias()), int(req.GetMinorVersion()), facts, int(req.GetWorkers()), req.GetDebug(),
+		func(res models.BatchExecutionResult) {
+			if sendErr != nil {
+				return
+			}
+			out := &enginepb.BatchResult{
+				Index:       int32(res.Index),
+				Status:      res.Status,
+				Logs:        res.Logs,
+				Error:       res.Error,
+				TimeTakenMs: res.TimeTaken,
+			}
+			if res.Data != nil {
+				data, err := structpb.NewStruct(res.Data)
+				if err != nil {
+					out.Status = "failure"
+					out.Error = "unable to encode engine output: " + err.Error()
+				}
+				out.Data = data
+			}
+			sendErr = stream.Send(out)
+		})
+	if err != nil {
+		return grpcError(stream.Context(), err)
+	}
+	return sendErr
+}
+
+func (h *ExecutionGRPCHandler) Validate(ctx context.Context, req *enginepb.ValidateRequest) (*enginepb.ValidateResponse, error) {
+	problems, err := h.service.ValidateEngine(ctx, req.GetNimbId(), service.VersionRef(int(req.GetVersion()), req.GetAlias()), int(req.GetMinorVersion()))
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return &enginepb.ValidateResponse{Valid: len(problems) == 0, Errors: problems}, nil
+}
+
+// grpcMeta reads the caller identity and correlation ID from the incoming metadata
+func grpcMeta(ctx context.Context) models.ExecutionMeta {
+	meta := models.ExecutionMeta{Channel: "grpc"}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return meta
+	}
+	first := func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+	meta.Caller = first("user_id")
+	meta.OrgID = first("org_id")
+	meta.CorrelationID = first("x-correlation-id")
+	return meta
+}
+
+// grpcError maps service errors onto gRPC status codes
+func grpcError(ctx context.Context, err error) error {
+	var invalid *service.InputValidationError
+	switch {
+	case errors.As(err, &invalid):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(ctx.Err(), context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	default:
+		return status.Errorf(codes.Internal, "Execution failed: %v", err)
+	}
+}
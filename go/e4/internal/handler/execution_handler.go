@@ -23,4 +23,12 @@ func (h *ExecutionHandler) RegisterRoutes(router *gin.RouterGroup) {
 	router.DELETE("/engine", h.service.ArchiveEngine)
 	router.PUT("/engine/clone", h.service.CloneEngine)
 	router.POST("/engine/invoke", h.service.HandleRuleExecution)
+	router.POST("/engine/invoke/batch", h.service.HandleBatchExecution)
+	router.POST("/engine/executions/list", h.service.GetAllExecutions)
+	router.GET("/engine/execution", h.service.GetExecutionByID)
+	router.POST("/engine/replay", h.service.HandleReplay)
+	router.GET("/engine/shadow/summary", h.service.GetShadowSummary)
+	router.PUT("/engine/traffic-split", h.service.SaveTrafficSplit)
+	router.GET("/engine/traffic-split", h.service.GetTrafficSplit)
+	router.DELETE("/engine/traffic-split", h.service.DeleteTrafficSplit)
 }
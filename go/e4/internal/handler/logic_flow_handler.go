@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/service"
+)
+
+type LogicFlowHandler struct {
+	service *service.LogicFlowService
+}
+
+func NewLogicFlowHandler(svc *service.LogicFlowService) *LogicFlowHandler {
+	return &LogicFlowHandler{
+		service: svc,
+	}
+}
+
+func (h *LogicFlowHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.POST("/logic-flow/invoke", h.service.HandleLogicFlowExecution)
+	router.POST("/logic-flow/convert", h.service.ConvertLogicFlow)
+}
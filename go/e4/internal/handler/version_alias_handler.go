@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/service"
+)
+
+type VersionAliasHandler struct {
+	service *service.VersionAliasService
+}
+
+func NewVersionAliasHandler(svc *service.VersionAliasService) *VersionAliasHandler {
+	return &VersionAliasHandler{
+		service: svc,
+	}
+}
+
+func (h *VersionAliasHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.PUT("/alias", h.service.RepointAlias)
+	router.GET("/aliases", h.service.GetAllAliases)
+	router.GET("/alias/history", h.service.GetAliasHistory)
+}
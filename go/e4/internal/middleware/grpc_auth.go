@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCAuthUnary requires the same user_id and org_id identity as AuthMiddleware, sent as metadata
+func GRPCAuthUnary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := checkGRPCIdentity(ctx); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func GRPCAuthStream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := checkGRPCIdentity(ss.Context()); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func checkGRPCIdentity(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if len(md.Get("user_id")) == 0 || md.Get("user_id")[0] == "" {
+		return status.Error(codes.Unauthenticated, "User name metadata required")
+	}
+	if len(md.Get("org_id")) == 0 || md.Get("org_id")[0] == "" {
+		return status.Error(codes.Unauthenticated, "ORG ID metadata required")
+	}
+	return nil
+}
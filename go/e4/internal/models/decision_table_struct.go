@@ -1,16 +1,49 @@
 package models
 
+import "time"
+
 type DecisionTable struct {
-	NIMB_ID         string          `bson:"nimb_id" json:"nimb_id"`
-	Description     string          `json:"description" gorm:"type:text" bson:"description"`
-	NoOfRows        int             `json:"no_of_rows" gorm:"not null" bson:"no_of_rows"` //
-	NoOfInputs      int             `json:"no_of_inputs" gorm:"not null" bson:"no_of_inputs"`
-	NoOfOutputs     int             `json:"no_of_outputs" gorm:"not null" bson:"no_of_outputs"` //
-	Name            string          `json:"name" gorm:"type:varchar(255);not null" bson:"name"`
-	HitPolicy       string          `json:"hit_policy" gorm:"type:varchar(50)" bson:"hit_policy"` // UNIQUE, FIRST, PRIORITY, COLLECT, COLLECT_SUM, COLLECT_COUNT
-	InputsColumns   []Variables     `json:"input_columns" gorm:"type:jsonb" bson:"input_columns"`
-	OutputsColumns  []Variables     `json:"output_columns" gorm:"type:jsonb" bson:"output_columns"`
-	VariablePackage VariablePackage `json:"variable_package" gorm:"type:jsonb" bson:"variable_package"`
-	Rules           [][]Variables   `json:"rules" gorm:"type:jsonb" bson:"rules"` // Rows of input criteria followed by output values
-	Audit           Audit           `json:"audit" gorm:"type:jsonb" bson:"audit"`
+	NIMB_ID            string                `bson:"nimb_id" json:"nimb_id"`
+	Description        string                `json:"description" gorm:"type:text" bson:"description"`
+	NoOfRows           int                   `json:"no_of_rows" gorm:"not null" bson:"no_of_rows"` //
+	NoOfInputs         int                   `json:"no_of_inputs" gorm:"not null" bson:"no_of_inputs"`
+	NoOfOutputs        int                   `json:"no_of_outputs" gorm:"not null" bson:"no_of_outputs"` //
+	Name               string                `json:"name" gorm:"type:varchar(255);not null" bson:"name"`
+	HitPolicy          string                `json:"hit_policy" gorm:"type:varchar(50)" bson:"hit_policy"`               // UNIQUE, FIRST, PRIORITY, COLLECT, COLLECT_SUM, COLLECT_COUNT
+	ExpressionLanguage string                `json:"expression_language,omitempty" bson:"expression_language,omitempty"` // Syntax of input entries: FEEL when empty, or NIMBUS
+	InputsColumns      []Variables           `json:"input_columns" gorm:"type:jsonb" bson:"input_columns"`
+	OutputsColumns     []Variables           `json:"output_columns" gorm:"type:jsonb" bson:"output_columns"`
+	VariablePackage    VariablePackage       `json:"variable_package" gorm:"type:jsonb" bson:"variable_package"`
+	Rules              [][]Variables         `json:"rules" gorm:"type:jsonb" bson:"rules"`                               // Rows of input criteria followed by output values
+	RulesMeta          []RuleMeta            `json:"rules_meta,omitempty" gorm:"type:jsonb" bson:"rules_meta,omitempty"` // None, or one per row of Rules
+	Requires           []DecisionRequirement `json:"requires,omitempty" gorm:"type:jsonb" bson:"requires,omitempty"`     // Tables evaluated first, whose outputs feed this table's inputs
+	TestCases          []DecisionTestCase    `json:"test_cases,omitempty" gorm:"type:jsonb" bson:"test_cases,omitempty"` // Run on every save of the version
+	Audit              Audit                 `json:"audit" gorm:"type:jsonb" bson:"audit"`
+}
+
+// DecisionRequirement names a decision table version whose outputs another table reads as inputs,
+// like an information requirement in a DMN decision requirements graph
+type DecisionRequirement struct {
+	NIMB_ID string `json:"nimb_id" bson:"nimb_id"`
+	Version int    `json:"version" bson:"version"`
+}
+
+// DecisionTestCase is a fact document together with what evaluating the table on it must give.
+// Expected maps variable keys, usually of output columns, to their values; ExpectedError instead
+// expects evaluation to fail with an error containing it, such as a hit policy violation.
+type DecisionTestCase struct {
+	Name           string                 `json:"name" bson:"name"`
+	Input          map[string]interface{} `json:"input" bson:"input"`
+	Expected       map[string]interface{} `json:"expected,omitempty" bson:"expected,omitempty"`
+	ExpectedError  string                 `json:"expected_error,omitempty" bson:"expected_error,omitempty"`
+	EvaluationDate *time.Time             `json:"evaluation_date,omitempty" bson:"evaluation_date,omitempty"` // Now when empty
+}
+
+// RuleMeta holds the details of a rule row that are not cells. A row is only effective from
+// ValidFrom, inclusive, up to ValidTo, exclusive; either bound may be left open.
+type RuleMeta struct {
+	ID         string     `json:"id,omitempty" bson:"id,omitempty"` // Rule id of an imported DMN file
+	Annotation string     `json:"annotation,omitempty" bson:"annotation,omitempty"`
+	ValidFrom  *time.Time `json:"valid_from,omitempty" bson:"valid_from,omitempty"`
+	ValidTo    *time.Time `json:"valid_to,omitempty" bson:"valid_to,omitempty"`
 }
@@ -7,7 +7,13 @@ type WorkflowDef struct {
 	Engine   string         `json:"engine_name"`
 	Pipeline []PipelineStep `json:"pipeline"`
 	Metadata []VariableMeta `json:"variable_metadata"`
-	Audit    Audit          `json:"audit" bson:"audit"`
+	// Declared input contract; facts are validated against it before execution when it has variables
+	VariablePackage VariablePackage `bson:"variable_package" json:"variable_package"`
+	// Declared output fields; when set, responses carry only these fields, type-checked
+	OutputContract []Variables `bson:"output_contract,omitempty" json:"output_contract,omitempty"`
+	// Version shadowed on live traffic; its output is compared with this version's and never returned
+	ChallengerVersion int   `bson:"challenger_version,omitempty" json:"challenger_version,omitempty"`
+	Audit             Audit `json:"audit" bson:"audit"`
 }
 
 type VariableMeta struct {
@@ -19,7 +25,7 @@ type VariableMeta struct {
 type PipelineStep struct {
 	Type string `json:"type"`
 
-	// Condition fields
+	// Condition fields. An assignment with a statement assigns the value of that expression.
 	Statement string         `json:"statement,omitempty"`
 	Children  []PipelineStep `json:"children,omitempty"`
 
@@ -0,0 +1,233 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ExecutionMeta identifies who asked for an execution and through which channel
+type ExecutionMeta struct {
+	Caller        string
+	OrgID         string
+	CorrelationID string
+	Channel       string // rest, amqp, grpc
+	Route         string // pinned, weighted, sticky; how the served version was chosen
+}
+
+// ExecutionResult is the outcome of running one fact document through an engine version
+type ExecutionResult struct {
+	ExecutionID  string
+	NIMB_ID      string
+	Version      int
+	MinorVersion int
+	Data         map[string]interface{} // projected onto the output contract
+	Raw          map[string]interface{} // whole data object the script left behind
+	Logs         []string
+	Duration     time.Duration
+}
+
+// ExecutionRecord is the persisted history of one engine execution, expired by a TTL index on executed_at
+type ExecutionRecord struct {
+	ExecutionID   string                 `bson:"execution_id" json:"execution_id"`
+	NIMB_ID       string                 `bson:"nimb_id" json:"nimb_id"`
+	Version       int                    `bson:"version" json:"version"`
+	MinorVersion  int                    `bson:"minor_version" json:"minor_version"`
+	Caller        string                 `bson:"caller" json:"caller"`
+	OrgID         string                 `bson:"org_id" json:"org_id"`
+	CorrelationID string                 `bson:"correlation_id,omitempty" json:"correlation_id,omitempty"`
+	Channel       string                 `bson:"channel" json:"channel"`
+	Route         string                 `bson:"route,omitempty" json:"route,omitempty"`
+	Input         map[string]interface{} `bson:"input,omitempty" json:"input,omitempty"`
+	InputHash     string                 `bson:"input_hash" json:"input_hash"`
+	Output        map[string]interface{} `bson:"output,omitempty" json:"output,omitempty"`
+	Trace         []string               `bson:"trace,omitempty" json:"trace,omitempty"`
+	Outcome       string                 `bson:"outcome" json:"outcome"` // success, failure
+	Error         string                 `bson:"error,omitempty" json:"error,omitempty"`
+	DurationMs    int64                  `bson:"duration_ms" json:"duration_ms"`
+	ExecutedAt    time.Time              `bson:"executed_at" json:"executed_at"`
+}
+
+// BatchExecutionResult is one line of a batch invocation response, streamed back in input order
+type BatchExecutionResult struct {
+	Index     int                    `json:"index"`
+	Status    string                 `json:"status"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	Logs      []string               `json:"logs,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+	TimeTaken int64                  `json:"time_taken_ms"`
+}
+
+// ExecutionRequestMessage is an engine invocation received over RabbitMQ
+type ExecutionRequestMessage struct {
+	NIMB_ID       string                 `json:"nimb_id"`
+	Version       int                    `json:"version"`
+	Alias         string                 `json:"alias,omitempty"` // takes precedence over version
+	Facts         map[string]interface{} `json:"facts"`
+	CorrelationID string                 `json:"correlation_id"`
+	ReplyTo       string                 `json:"reply_to"`
+	Caller        string                 `json:"caller,omitempty"`
+	OrgID         string                 `json:"org_id,omitempty"`
+	Debug         bool                   `json:"debug,omitempty"`
+}
+
+// ExecutionReplyMessage is published back to the exchange on the request's reply-to routing key
+type ExecutionReplyMessage struct {
+	CorrelationID string                 `json:"correlation_id"`
+	ExecutionID   string                 `json:"execution_id,omitempty"`
+	NIMB_ID       string                 `json:"nimb_id"`
+	Version       int                    `json:"version"`
+	Status        string                 `json:"status"`
+	Data          map[string]interface{} `json:"data,omitempty"`
+	Logs          []string               `json:"logs,omitempty"`
+	Error         string                 `json:"error,omitempty"`
+	TimeTaken     int64                  `json:"time_taken_ms"`
+}
+
+// ExecutionHistoryFilter selects execution records; zero values are ignored
+type ExecutionHistoryFilter struct {
+	NIMB_ID       string     `json:"nimb_id"`
+	Version       int        `json:"version"`
+	Outcome       string     `json:"outcome"`
+	CorrelationID string     `json:"correlation_id"`
+	From          *time.Time `json:"from"`
+	To            *time.Time `json:"to"`
+	Page          int        `json:"page"`
+	PageSize      int        `json:"page_size"`
+}
+
+// FieldChange is one difference between two JSON documents, addressed by a JSON pointer
+type FieldChange struct {
+	Op     string      `bson:"op" json:"op"` // add, remove, replace
+	Path   string      `bson:"path" json:"path"`
+	Before interface{} `bson:"before,omitempty" json:"before,omitempty"`
+	After  interface{} `bson:"after,omitempty" json:"after,omitempty"`
+}
+
+// ReplayRequest re-runs stored executions of an engine against a candidate version. Executions are
+// picked by ID when ExecutionIDs is set and by Filter otherwise.
+type ReplayRequest struct {
+	NIMB_ID          string                 `json:"nimb_id"`
+	CandidateVersion int                    `json:"candidate_version"`
+	ExecutionIDs     []string               `json:"execution_ids"`
+	Filter           ExecutionHistoryFilter `json:"filter"`
+	Limit            int                    `json:"limit"`
+	IncludeUnchanged bool                   `json:"include_unchanged"`
+}
+
+// ReplayRecord compares one stored execution with its replay on the candidate version
+type ReplayRecord struct {
+	ExecutionID      string        `json:"execution_id"`
+	BaselineVersion  int           `json:"baseline_version"`
+	BaselineOutcome  string        `json:"baseline_outcome"`
+	CandidateOutcome string        `json:"candidate_outcome,omitempty"`
+	Status           string        `json:"status"` // unchanged, changed, failed, skipped
+	Changes          []FieldChange `json:"changes,omitempty"`
+	Error            string        `json:"error,omitempty"`
+}
+
+// ReplaySummary aggregates a replay run. FieldChanges counts the replayed records that changed each path.
+type ReplaySummary struct {
+	Total        int            `json:"total"`
+	Replayed     int            `json:"replayed"`
+	Unchanged    int            `json:"unchanged"`
+	Changed      int            `json:"changed"`
+	Failed       int            `json:"failed"`
+	Skipped      int            `json:"skipped"`
+	OutcomeFlips int            `json:"outcome_flips"`
+	ChangeRate   float64        `json:"change_rate"`
+	FieldChanges map[string]int `json:"field_changes"`
+	TimeTakenMs  int64          `json:"time_taken_ms"`
+}
+
+// ReplayReport is the response of a replay run
+type ReplayReport struct {
+	NIMB_ID          string         `json:"nimb_id"`
+	CandidateVersion int            `json:"candidate_version"`
+	Summary          ReplaySummary  `json:"summary"`
+	Records          []ReplayRecord `json:"records"`
+}
+
+// JSONPatchOperation is one RFC 6902 operation. Value is written for every op but remove, even when null.
+type JSONPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+func (o JSONPatchOperation) MarshalJSON() ([]byte, error) {
+	if o.Op == "remove" {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{o.Op, o.Path})
+	}
+	type operation JSONPatchOperation
+	return json.Marshal(operation(o))
+}
+
+// FieldError is one input field that does not match the engine's variable package
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ShadowComparison is the outcome of running a challenger version on a live request next to its champion
+type ShadowComparison struct {
+	ComparisonID      string        `bson:"comparison_id" json:"comparison_id"`
+	ExecutionID       string        `bson:"execution_id,omitempty" json:"execution_id,omitempty"`
+	NIMB_ID           string        `bson:"nimb_id" json:"nimb_id"`
+	OrgID             string        `bson:"org_id" json:"org_id"`
+	ChampionVersion   int           `bson:"champion_version" json:"champion_version"`
+	ChallengerVersion int           `bson:"challenger_version" json:"challenger_version"`
+	ChampionOutcome   string        `bson:"champion_outcome" json:"champion_outcome"`
+	ChallengerOutcome string        `bson:"challenger_outcome" json:"challenger_outcome"`
+	ChampionMs        int64         `bson:"champion_ms" json:"champion_ms"`
+	ChallengerMs      int64         `bson:"challenger_ms" json:"challenger_ms"`
+	Matched           bool          `bson:"matched" json:"matched"`
+	Changes           []FieldChange `bson:"changes,omitempty" json:"changes,omitempty"`
+	Error             string        `bson:"error,omitempty" json:"error,omitempty"`
+	ComparedAt        time.Time     `bson:"compared_at" json:"compared_at"`
+}
+
+// ShadowSummary aggregates the comparisons of one champion/challenger pair
+type ShadowSummary struct {
+	ChampionVersion    int                `bson:"champion_version" json:"champion_version"`
+	ChallengerVersion  int                `bson:"challenger_version" json:"challenger_version"`
+	Total              int64              `bson:"total" json:"total"`
+	Matched            int64              `bson:"matched" json:"matched"`
+	Mismatched         int64              `bson:"mismatched" json:"mismatched"`
+	ChallengerFailures int64              `bson:"challenger_failures" json:"challenger_failures"`
+	MatchRate          float64            `bson:"match_rate" json:"match_rate"`
+	AvgChampionMs      float64            `bson:"avg_champion_ms" json:"avg_champion_ms"`
+	AvgChallengerMs    float64            `bson:"avg_challenger_ms" json:"avg_challenger_ms"`
+	MaxChampionMs      int64              `bson:"max_champion_ms" json:"max_champion_ms"`
+	MaxChallengerMs    int64              `bson:"max_challenger_ms" json:"max_challenger_ms"`
+	FirstComparedAt    time.Time          `bson:"first_compared_at" json:"first_compared_at"`
+	LastComparedAt     time.Time          `bson:"last_compared_at" json:"last_compared_at"`
+	TopChangedFields   []FieldChangeCount `bson:"top_changed_fields" json:"top_changed_fields"`
+}
+
+// FieldChangeCount counts the comparisons in which a field differed
+type FieldChangeCount struct {
+	ChampionVersion   int    `bson:"champion_version" json:"-"`
+	ChallengerVersion int    `bson:"challenger_version" json:"-"`
+	Path              string `bson:"path" json:"path"`
+	Count             int64  `bson:"count" json:"count"`
+}
+
+// TrafficSplit routes invocations of an engine that name no version across several versions.
+// Weighted picks a version at random in proportion to the weights; sticky hashes HashField from
+// the facts, so the same customer keeps landing on the same version while the weights stay put.
+type TrafficSplit struct {
+	NIMB_ID   string         `bson:"nimb_id" json:"nimb_id"`
+	OrgID     string         `bson:"org_id" json:"org_id"`
+	Strategy  string         `bson:"strategy" json:"strategy"` // weighted, sticky
+	HashField string         `bson:"hash_field,omitempty" json:"hash_field,omitempty"`
+	Routes    []TrafficRoute `bson:"routes" json:"routes"`
+	Audit     Audit          `bson:"audit" json:"audit"`
+}
+
+type TrafficRoute struct {
+	Version int `bson:"version" json:"version"`
+	Weight  int `bson:"weight" json:"weight"`
+}
@@ -7,7 +7,7 @@ type LogicFlow struct {
 	Active          bool            `bson:"active" json:"active"`
 	NoOfBranches    int             `bson:"no_of_branches" json:"no_of_branches"`
 	VariablePackage VariablePackage `bson:"variable_package" json:"variable_package"`
-	Steps           []LogicalStep   `bson:"steps" json:"steps"`
+	LogicalSteps    []LogicalStep   `bson:"logical_steps" json:"logical_steps"`
 	Audit           Audit           `bson:"audit" json:"audit"`
 }
 
@@ -25,6 +25,8 @@ type Condition struct {
 	Logical      string        `json:"logical" gorm:"type:varchar(50)" bson:"logical"`
 	OpValue      interface{}   `json:"op_value" gorm:"type:jsonb" bson:"op_value"`
 	ArrayFilters []ArrayFilter `json:"array_filters,omitempty" gorm:"type:jsonb" bson:"array_filters,omitempty"`
+	// How each array of the variable key is quantified; arrays without one use Operator
+	Quantifiers []ArrayQuantifier `json:"quantifiers,omitempty" gorm:"type:jsonb" bson:"quantifiers,omitempty"`
 
 	Conditions []Condition `json:"conditions" gorm:"type:jsonb" bson:"conditions"`
 }
@@ -43,3 +45,12 @@ type ArrayFilter struct {
 	Logical   string      `json:"logical" gorm:"type:varchar(50)" bson:"logical"`
 	OpValue   interface{} `json:"op_value" gorm:"type:jsonb" bson:"op_value"`
 }
+
+// ArrayQuantifier says how many elements of an array must satisfy the rest of a condition: any,
+// all, none, or a count compared with Logical and OpValue, e.g. count gte 2
+type ArrayQuantifier struct {
+	ArrayName  string      `json:"array_name" gorm:"type:varchar(255)" bson:"array_name"`
+	Quantifier string      `json:"quantifier" gorm:"type:varchar(50)" bson:"quantifier"`
+	Logical    string      `json:"logical,omitempty" gorm:"type:varchar(50)" bson:"logical,omitempty"`
+	OpValue    interface{} `json:"op_value,omitempty" gorm:"type:jsonb" bson:"op_value,omitempty"`
+}
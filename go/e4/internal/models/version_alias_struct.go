@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// VersionAlias is a movable name such as latest, stable or prod that points one entity at a
+// version/minor-version pair, so callers can follow republished rules without redeploying.
+type VersionAlias struct {
+	EntityType   string `bson:"entity_type" json:"entity_type"` // engine, decision_table, logic_flow, orchestration
+	NIMB_ID      string `bson:"nimb_id" json:"nimb_id"`
+	Alias        string `bson:"alias" json:"alias"`
+	Version      int    `bson:"version" json:"version"`
+	MinorVersion int    `bson:"minor_version" json:"minor_version"`
+	Audit        Audit  `bson:"audit" json:"audit"`
+}
+
+// VersionAliasRequest repoints an alias. A zero MinorVersion pins the current minor version of
+// Version. When Expected is set the alias only moves if it still points there.
+type VersionAliasRequest struct {
+	EntityType   string          `json:"entity_type"`
+	NIMB_ID      string          `json:"nimb_id"`
+	Alias        string          `json:"alias"`
+	Version      int             `json:"version"`
+	MinorVersion int             `json:"minor_version"`
+	Expected     *VersionPointer `json:"expected,omitempty"`
+	Reason       string          `json:"reason"`
+}
+
+type VersionPointer struct {
+	Version      int `bson:"version" json:"version"`
+	MinorVersion int `bson:"minor_version" json:"minor_version"`
+}
+
+// VersionAliasHistory records one move of an alias; From is empty when the alias was created
+type VersionAliasHistory struct {
+	EntityType string          `bson:"entity_type" json:"entity_type"`
+	NIMB_ID    string          `bson:"nimb_id" json:"nimb_id"`
+	Alias      string          `bson:"alias" json:"alias"`
+	From       *VersionPointer `bson:"from,omitempty" json:"from,omitempty"`
+	To         VersionPointer  `bson:"to" json:"to"`
+	Reason     string          `bson:"reason,omitempty" json:"reason,omitempty"`
+	ChangedBy  string          `bson:"changed_by" json:"changed_by"`
+	ChangedAt  time.Time       `bson:"changed_at" json:"changed_at"`
+}
+
+// VersionedEntity is the part shared by every versioned entity document
+type VersionedEntity struct {
+	NIMB_ID string `bson:"nimb_id" json:"nimb_id"`
+	Audit   Audit  `bson:"audit" json:"audit"`
+}
@@ -36,6 +36,21 @@ func (r *GenericRepository[T]) UpdateOne(filter interface{}, update interface{},
 	return r.Coll.UpdateOne(r.Ctx, filter, update, opts...)
 }
 
+func (r *GenericRepository[T]) UpdateMany(filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	return r.Coll.UpdateMany(r.Ctx, filter, update, opts...)
+}
+
+// FindOneAndUpdate atomically updates one document and returns it as selected by the options
+// (before the update by default).
+func (r *GenericRepository[T]) FindOneAndUpdate(filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) (*T, error) {
+	var result T
+	err := r.Coll.FindOneAndUpdate(r.Ctx, filter, update, opts...).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 func (r *GenericRepository[T]) DeleteOne(filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
 	return r.Coll.DeleteOne(r.Ctx, filter, opts...)
 }
@@ -114,3 +129,13 @@ func (r *GenericRepository[T]) ListIndexes(opts ...*options.ListIndexesOptions)
 	}
 	return indexes, nil
 }
+
+// SetIndexExpiry changes the expireAfterSeconds of the TTL index on keys with collMod, as creating
+// the index again with another value fails
+func (r *GenericRepository[T]) SetIndexExpiry(keys bson.D, seconds int32) error {
+	command := bson.D{
+		{Key: "collMod", Value: r.Coll.Name()},
+		{Key: "index", Value: bson.D{{Key: "keyPattern", Value: keys}, {Key: "expireAfterSeconds", Value: seconds}}},
+	}
+	return r.Coll.Database().RunCommand(r.Ctx, command).Err()
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+)
+
+// Upper bound for loading the engine and publishing the reply of one queued request
+const asyncExecutionTimeout = 30 * time.Second
+
+// StartExecutionConsumer consumes engine invocations from the execution queue. Each request is
+// answered on the exchange with its reply-to routing key, carrying the request's correlation ID.
+func (lfs *ExecutionService) StartExecutionConsumer() error {
+	queue := lfs.cfg.RabbitMQ.ExecutionQueue
+	if err := lfs.rabbitMQ.DeclareQueue(queue, true); err != nil {
+		return err
+	}
+	slog.Info("Consuming execution requests", "queue", queue, "routing_key", lfs.cfg.RabbitMQ.ExecutionRoutingKey)
+	return lfs.rabbitMQ.ConsumeWithRoutingKey(queue, lfs.cfg.RabbitMQ.ExecutionRoutingKey, lfs.handleExecutionMessage)
+}
+
+// handleExecutionMessage only returns an error when the reply could not be published, so the
+// request is requeued once and rejected if that fails too. Malformed or failing requests are
+// answered (or dropped) and acked.
+func (lfs *ExecutionService) handleExecutionMessage(body []byte) error {
+	var req models.ExecutionRequestMessage
+	if err := json.Unmarshal(body, &req); err != nil {
+		slog.Error("Dropping malformed execution request", "error", err)
+		return nil
+	}
+	if req.ReplyTo == "" {
+		slog.Error("Dropping execution request without reply_to", "correlation_id", req.CorrelationID)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), asyncExecutionTimeout)
+	defer cancel()
+
+	reply := lfs.executeRequestMessage(ctx, req)
+	if err := lfs.rabbitMQ.Publish(ctx, req.ReplyTo, reply); err != nil {
+		slog.Error("Failed to publish execution reply", "correlation_id", req.CorrelationID, "error", err)
+		return err
+	}
+	slog.Info("Published execution reply", "correlation_id", req.CorrelationID, "status", reply.Status)
+	return nil
+}
+
+func (lfs *ExecutionService) executeRequestMessage(ctx context.Context, req models.ExecutionRequestMessage) models.ExecutionReplyMessage {
+	reply := models.ExecutionReplyMessage{
+		CorrelationID: req.CorrelationID,
+		NIMB_ID:       req.NIMB_ID,
+		Version:       req.Version,
+		Status:        "failure",
+	}
+	meta := models.ExecutionMeta{Caller: req.Caller, OrgID: req.OrgID, CorrelationID: req.CorrelationID, Channel: "amqp"}
+	result, err := lfs.Invoke(ctx, meta, req.NIMB_ID, VersionRef(req.Version, req.Alias), req.Facts)
+	if result != nil {
+		reply.ExecutionID = result.ExecutionID
+		reply.Version = result.Version
+		reply.TimeTaken = result.Duration.Milliseconds()
+	}
+	if err != nil {
+		reply.Error = "Execution failed: " + err.Error()
+		return reply
+	}
+	reply.Status = "success"
+	reply.Data = result.Data
+	if req.Debug {
+		reply.Logs = result.Logs
+	}
+	return reply
+}
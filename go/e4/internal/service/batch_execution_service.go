@@ -0,0 +1,262 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/engine"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+)
+
+// Largest single NDJSON line accepted in a batch body
+const maxBatchLineSize = 10 * 1024 * 1024
+
+// A null record decodes without error into a nil map, which is not a fact document
+var errNullRecord = errors.New("record is null, expected a JSON object")
+
+type batchRecord struct {
+	index int
+	facts map[string]interface{}
+	err   error
+}
+
+// HandleBatchExecution runs a JSON array or NDJSON stream of fact documents through one engine
+// version on a bounded worker pool and streams the results back as NDJSON, in input order.
+func (lfs *ExecutionService) HandleBatchExecution(c *gin.Context) {
+	nimbID := c.Query("nimb_id")
+	pointer, _, err := lfs.resolveEngineRef(c.Request.Context(), nimbID, c.Query("version"))
+	if HandleError(c, err, "Failed to resolve engine version for execution") {
+		return
+	}
+	eng, err := lfs.findEngineVersion(c.Request.Context(), nimbID, pointer)
+	if HandleError(c, err, "Failed to fetch logic flow for execution") {
+		return
+	}
+	jsCode := lfs.engineScript(c.Request.Context(), eng)
+	workers := lfs.batchWorkers(c.Query("workers"))
+	debug := c.GetHeader("X-NIMBUS-DEBUG") == "YES"
+	slog.Info("Starting batch execution", "nimb_id", nimbID, "version", eng.Audit.Version, "workers", workers)
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Status(http.StatusOK)
+	encoder := json.NewEncoder(c.Writer)
+	produce := func(dispatch func(batchRecord) bool) {
+		readBatchRecords(c.Request.Body, dispatch)
+	}
+	processed, failed := executeBatch(c.Request.Context(), eng, jsCode, workers, debug, produce, func(out models.BatchExecutionResult) {
+		if err := encoder.Encode(out); err != nil {
+			slog.Error("Failed to write batch result", "index", out.Index, "error", err)
+		}
+		c.Writer.Flush()
+	})
+	slog.Info("Finished batch execution", "nimb_id", nimbID, "records", processed, "failed", failed)
+}
+
+// InvokeBatch runs already decoded fact documents through the engine version a version number or
+// alias refers to and passes each result to emit in input order. It is the transport-neutral
+// counterpart of HandleBatchExecution.
+func (lfs *ExecutionService) InvokeBatch(ctx context.Context, nimbID, ref string, minorVersion int, facts []map[string]interface{}, workers int, debug bool, emit func(models.BatchExecutionResult)) error {
+	eng, err := lfs.findEngineRef(ctx, nimbID, ref, minorVersion)
+	if err != nil {
+		return err
+	}
+	jsCode := lfs.engineScript(ctx, eng)
+	produce := func(dispatch func(batchRecord) bool) {
+		for index, doc := range facts {
+			if !dispatch(batchRecord{index: index, facts: doc}) {
+				return
+			}
+		}
+	}
+	processed, failed := executeBatch(ctx, eng, jsCode, lfs.batchWorkers(strconv.Itoa(workers)), debug, produce, emit)
+	slog.Info("Finished batch execution", "nimb_id", nimbID, "records", processed, "failed", failed)
+	return ctx.Err()
+}
+
+// executeBatch fans records out to a bounded worker pool and emits the results, projected onto
+// the engine's output contract, in input order. Records failing validation against its variable
+// package are reported as failed without running.
+// produce hands records to dispatch, which blocks while the in-flight window is full and returns
+// false once ctx is done. It returns the number of records emitted and how many of them failed.
+func executeBatch(ctx context.Context, eng *models.WorkflowDef, jsCode string, workers int, debug bool, produce func(dispatch func(batchRecord) bool), emit func(models.BatchExecutionResult)) (int, int) {
+	// window bounds the records in flight, so a slow record cannot make the reorder buffer grow unbounded
+	window := make(chan struct{}, workers*2)
+	records := make(chan batchRecord)
+	results := make(chan models.BatchExecutionResult)
+
+	go func() {
+		defer close(records)
+		produce(func(rec batchRecord) bool {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return false
+			}
+			select {
+			case records <- rec:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for rec := range records {
+				results <- runBatchRecord(ctx, eng, jsCode, rec, debug)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	pending := make(map[int]models.BatchExecutionResult)
+	next, failed := 0, 0
+	for res := range results {
+		pending[res.Index] = res
+		for {
+			out, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			if out.Status != "success" {
+				failed++
+			}
+			emit(out)
+			<-window
+			next++
+		}
+	}
+	return next, failed
+}
+
+// batchWorkers resolves the worker pool size from the request, bounded by configuration
+func (lfs *ExecutionService) batchWorkers(requested string) int {
+	workers := lfs.cfg.Execution.BatchWorkers
+	if n, err := strconv.Atoi(requested); err == nil && n > 0 {
+		workers = n
+	}
+	if workers > lfs.cfg.Execution.BatchMaxWorkers {
+		workers = lfs.cfg.Execution.BatchMaxWorkers
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	return workers
+}
+
+// readBatchRecords decodes the body as a JSON array when it starts with '[' and as NDJSON otherwise,
+// handing each record to dispatch until the body ends or dispatch gives up.
+func readBatchRecords(body io.Reader, dispatch func(batchRecord) bool) {
+	reader := bufio.NewReader(body)
+	if firstByte(reader) == '[' {
+		decoder := json.NewDecoder(reader)
+		if _, err := decoder.Token(); err != nil {
+			dispatch(batchRecord{index: 0, err: err})
+			return
+		}
+		for index := 0; decoder.More(); index++ {
+			var facts map[string]interface{}
+			if err := decoder.Decode(&facts); err != nil {
+				// The decoder cannot resync inside an array, so the rest of the body is dropped
+				dispatch(batchRecord{index: index, err: err})
+				return
+			}
+			rec := batchRecord{index: index, facts: facts}
+			if facts == nil {
+				rec.err = errNullRecord
+			}
+			if !dispatch(rec) {
+				return
+			}
+		}
+		return
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), maxBatchLineSize)
+	index := 0
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		rec := batchRecord{index: index}
+		rec.err = json.Unmarshal(line, &rec.facts)
+		if rec.err == nil && rec.facts == nil {
+			rec.err = errNullRecord
+		}
+		if !dispatch(rec) {
+			return
+		}
+		index++
+	}
+	if err := scanner.Err(); err != nil {
+		dispatch(batchRecord{index: index, err: err})
+	}
+}
+
+// firstByte peeks past leading whitespace and returns the first significant byte of the body
+func firstByte(reader *bufio.Reader) byte {
+	for {
+		b, err := reader.Peek(1)
+		if err != nil {
+			return 0
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			reader.ReadByte()
+		default:
+			return b[0]
+		}
+	}
+}
+
+func runBatchRecord(ctx context.Context, eng *models.WorkflowDef, jsCode string, rec batchRecord, debug bool) models.BatchExecutionResult {
+	result := models.BatchExecutionResult{Index: rec.index}
+	if rec.err != nil {
+		result.Status = "failure"
+		result.Error = fmt.Sprintf("Invalid record payload: %v", rec.err)
+		return result
+	}
+	if err := validateFacts(eng.VariablePackage, rec.facts); err != nil {
+		result.Status = "failure"
+		result.Error = err.Error()
+		return result
+	}
+	start := time.Now()
+	finalData, logs, err := engine.ExecuteContext(ctx, jsCode, rec.facts)
+	result.TimeTaken = time.Since(start).Milliseconds()
+	if err == nil {
+		finalData, err = projectOutput(eng.OutputContract, finalData)
+	}
+	if err != nil {
+		result.Status = "failure"
+		result.Error = err.Error()
+		return result
+	}
+	result.Status = "success"
+	result.Data = finalData
+	if debug {
+		result.Logs = logs
+	}
+	return result
+}
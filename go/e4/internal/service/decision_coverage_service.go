@@ -0,0 +1,127 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/repository"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/utils"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/pkg/dtable"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultCoverageLimit = 1000
+	maxCoverageLimit     = 10000
+)
+
+// HandleDecisionTableCoverage evaluates a dataset of fact documents against a decision table
+// version and reports how often each row matched, the rows that never matched, the documents no row
+// matched and the distribution of the outputs. The dataset is a JSON array or NDJSON stream, sent
+// as the multipart field file or as the raw body. Rows are taken as effective at the
+// evaluation_date query parameter, or now.
+func (dts *DecisionTableService) HandleDecisionTableCoverage(c *gin.Context) {
+	at, responded := evaluationDate(c)
+	if responded {
+		return
+	}
+	coverage, responded := dts.startCoverage(c)
+	if responded {
+		return
+	}
+	body := c.Request.Body
+	if header, err := c.FormFile("file"); err == nil {
+		file, err := header.Open()
+		if HandleError(c, err, "Unable to read dataset") {
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+	var failed error
+	readBatchRecords(body, func(rec batchRecord) bool {
+		if rec.err != nil {
+			coverage.AddError(rec.index, rec.err)
+			return true
+		}
+		failed = coverage.Add(c.Request.Context(), rec.index, rec.facts, at)
+		return failed == nil
+	})
+	if HandleError(c, failed, "Coverage run interrupted") {
+		return
+	}
+	report := coverage.Report()
+	RespondJSON(c, http.StatusOK, "success", coverageMessage(report), report)
+}
+
+// HandleDecisionTableHistoryCoverage reports the coverage of a decision table version over the
+// inputs stored in the execution history of its compiled engine, newest first and up to the limit
+// query parameter. The body is an optional execution history filter; each input is evaluated with
+// the rows effective when it was executed.
+func (dts *DecisionTableService) HandleDecisionTableHistoryCoverage(c *gin.Context) {
+	var payload models.ExecutionHistoryFilter
+	if err := c.ShouldBindJSON(&payload); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid history filter", "details": err.Error()})
+		return
+	}
+	coverage, responded := dts.startCoverage(c)
+	if responded {
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	if limit < 1 {
+		limit = defaultCoverageLimit
+	}
+	if limit > maxCoverageLimit {
+		limit = maxCoverageLimit
+	}
+	filter := historyFilter(c.GetHeader("org_id"), payload)
+	filter["nimb_id"] = c.Query("nimb_id")
+	filter["input"] = bson.M{"$exists": true}
+	repo := repository.NewGenericRepository[models.ExecutionRecord](c.Request.Context(), dts.mongo.Database, executionHistoryCollection)
+	option := options.Find().
+		SetSort(bson.D{{Key: "executed_at", Value: -1}}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"input": 1, "executed_at": 1})
+	records, err := repo.FindMany(filter, option)
+	if HandleError(c, err, "Failed to fetch executions") {
+		return
+	}
+	for index, record := range records {
+		err := coverage.Add(c.Request.Context(), index, utils.NormalizeDocument(record.Input), record.ExecutedAt)
+		if HandleError(c, err, "Coverage run interrupted") {
+			return
+		}
+	}
+	report := coverage.Report()
+	RespondJSON(c, http.StatusOK, "success", coverageMessage(report), report)
+}
+
+// startCoverage compiles the table version named by the query and starts its coverage report. It
+// reports whether it responded instead.
+func (dts *DecisionTableService) startCoverage(c *gin.Context) (*dtable.Coverage, bool) {
+	table, err := dts.findTable(c.Request.Context(), c.Query("nimb_id"), c.Query("version"))
+	if HandleError(c, err, "Failed to fetch decision table for coverage") {
+		return nil, true
+	}
+	compiled, err := dts.compile(table)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "Decision table cannot be evaluated",
+			"details": err.Error(),
+		})
+		return nil, true
+	}
+	return dtable.NewCoverage(compiled), false
+}
+
+func coverageMessage(report *dtable.CoverageReport) string {
+	return fmt.Sprintf("%d documents evaluated, %d rows never matched, %d documents matched no row",
+		report.Documents, len(report.NeverMatched), report.Unmatched)
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/pkg/dtable"
+)
+
+// HandleDecisionGraphExecution evaluates a decision table together with the tables it requires,
+// directly or through other tables, on one fact document. Tables run in dependency order, so each
+// reads the outputs of the tables it requires from the facts, and every table considers the rows
+// effective at the same evaluation date. In debug mode it adds, per table, the outputs, matched
+// rows and trace.
+func (dts *DecisionTableService) HandleDecisionGraphExecution(c *gin.Context) {
+	var req map[string]interface{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+	at, responded := evaluationDate(c)
+	if responded {
+		return
+	}
+	ctx := c.Request.Context()
+	root, err := dts.findTable(ctx, c.Query("nimb_id"), c.Query("version"))
+	if HandleError(c, err, "Failed to fetch decision table for execution") {
+		return
+	}
+	tables, err := dtable.Resolve(root, func(nimbID string, version int) (*models.DecisionTable, error) {
+		return dts.findTable(ctx, nimbID, strconv.Itoa(version))
+	})
+	var cycle *dtable.CycleError
+	if errors.As(err, &cycle) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Decision requirements form a cycle", "details": cycle.Path})
+		return
+	}
+	if HandleError(c, err, "Failed to fetch required decision tables") {
+		return
+	}
+
+	debug := c.GetHeader("X-NIMBUS-DEBUG") == "YES"
+	start := time.Now()
+	decisions := []gin.H{}
+	for _, table := range tables {
+		compiled, err := dts.compile(table)
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "Decision table " + table.NIMB_ID + " cannot be evaluated",
+				"details": err.Error(),
+			})
+			return
+		}
+		result, err := compiled.EvaluateAt(ctx, req, at, debug)
+		decision := gin.H{"nimb_id": table.NIMB_ID, "name": table.Name, "version": table.Audit.Version, "minor_version": table.Audit.MinorVersion}
+		if result != nil {
+			decision["outputs"] = result.Outputs
+			decision["matched_rows"] = result.MatchedRows
+			decision["hit_policy"] = compiled.Policy.String()
+			decision["trace"] = result.Trace
+		}
+		decisions = append(decisions, decision)
+		if err != nil {
+			c.Header("X_TIME-TAKEN", strconv.FormatInt(time.Since(start).Milliseconds(), 10))
+			response := gin.H{
+				"error":   "Execution of decision table " + table.NIMB_ID + " failed",
+				"details": err.Error(),
+			}
+			if debug {
+				response["decisions"] = decisions
+			}
+			c.JSON(http.StatusInternalServerError, response)
+			return
+		}
+	}
+	c.Header("X_TIME-TAKEN", strconv.FormatInt(time.Since(start).Milliseconds(), 10))
+	if debug {
+		c.JSON(http.StatusOK, gin.H{"data": req, "decisions": decisions})
+		return
+	}
+	c.JSON(http.StatusOK, req)
+}
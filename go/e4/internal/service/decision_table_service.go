@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/config"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/engine"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/repository"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/pkg/cache"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/pkg/database"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/pkg/dtable"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Compiled tables kept in memory; past this, an arbitrary one is dropped for each new one
+const maxCompiledTables = 128
+
+// DecisionTableService evaluates the decision tables the core service stores
+type DecisionTableService struct {
+	mongo *database.MongoDB
+	redis *cache.RedisClient
+	cfg   *config.Config
+	// Compiled tables with their indexes, by NIMB_ID and version; versions never change once saved
+	mu       sync.Mutex
+	compiled map[string]*dtable.Table
+}
+
+func NewDecisionTableService(db *database.MongoDB, redisClient *cache.RedisClient, cfg *config.Config) *DecisionTableService {
+	return &DecisionTableService{
+		mongo:    db,
+		redis:    redisClient,
+		cfg:      cfg,
+		compiled: map[string]*dtable.Table{},
+	}
+}
+
+// HandleDecisionTableExecution evaluates one fact document against a decision table version and
+// returns the facts with the table's outputs set. Only the rows effective at the evaluation_date
+// query parameter, or now, are considered. In debug mode it adds the matched rows and a trace.
+func (dts *DecisionTableService) HandleDecisionTableExecution(c *gin.Context) {
+	nimbID := c.Query("nimb_id")
+	var req map[string]interface{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+	at, responded := evaluationDate(c)
+	if responded {
+		return
+	}
+	table, err := dts.findTable(c.Request.Context(), nimbID, c.Query("version"))
+	if HandleError(c, err, "Failed to fetch decision table for execution") {
+		return
+	}
+	compiled, err := dts.compile(table)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "Decision table cannot be evaluated",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	debug := c.GetHeader("X-NIMBUS-DEBUG") == "YES"
+	start := time.Now()
+	result, err := compiled.EvaluateAt(c.Request.Context(), req, at, debug)
+	c.Header("X_TIME-TAKEN", strconv.FormatInt(time.Since(start).Milliseconds(), 10))
+	if err != nil {
+		response := gin.H{
+			"error":   "Execution failed",
+			"details": err.Error(),
+		}
+		if debug && result != nil {
+			response["trace"] = result.Trace
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	if debug {
+		c.JSON(http.StatusOK, gin.H{
+			"data":            result.Data,
+			"outputs":         result.Outputs,
+			"matched_rows":    result.MatchedRows,
+			"hit_policy":      compiled.Policy.String(),
+			"evaluation_date": dtable.FormatDate(at),
+			"trace":           result.Trace,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, result.Data)
+}
+
+// evaluationDate reads the evaluation_date query parameter, a date or an RFC 3339 timestamp, and
+// falls back to now. It responds with 400 when the date does not parse and reports whether it did.
+func evaluationDate(c *gin.Context) (time.Time, bool) {
+	raw := c.Query("evaluation_date")
+	if raw == "" {
+		return time.Now(), false
+	}
+	at, err := dtable.ParseDate(raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid evaluation_date", "details": err.Error()})
+		return time.Time{}, true
+	}
+	return at, false
+}
+
+// compile returns the compiled form of a table version, compiling and indexing it on first use
+func (dts *DecisionTableService) compile(table *models.DecisionTable) (*dtable.Table, error) {
+	key := table.NIMB_ID + "_v" + strconv.Itoa(table.Audit.Version) + "." + strconv.Itoa(table.Audit.MinorVersion)
+	dts.mu.Lock()
+	compiled, ok := dts.compiled[key]
+	dts.mu.Unlock()
+	if ok {
+		return compiled, nil
+	}
+	compiled, err := dtable.Compile(*table)
+	if err != nil {
+		return nil, err
+	}
+	dts.mu.Lock()
+	defer dts.mu.Unlock()
+	if len(dts.compiled) >= maxCompiledTables {
+		for stale := range dts.compiled {
+			delete(dts.compiled, stale)
+			break
+		}
+	}
+	dts.compiled[key] = compiled
+	return compiled, nil
+}
+
+// CompileDecisionTable compiles a decision table version into an engine pipeline and saves it as
+// the engine version with the same NIMB_ID and version numbers, so that /engine/invoke runs the
+// table with the engine's script caching, limits, history, aliases and traffic splits. Compiling
+// the current minor version archives the engines compiled from the others.
+func (dts *DecisionTableService) CompileDecisionTable(c *gin.Context) {
+	table, err := dts.findTable(c.Request.Context(), c.Query("nimb_id"), c.Query("version"))
+	if HandleError(c, err, "Failed to fetch decision table for compilation") {
+		return
+	}
+	pipeline, err := dtable.ToPipeline(*table)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "Decision table cannot be compiled",
+			"details": err.Error(),
+		})
+		return
+	}
+	eng := models.WorkflowDef{
+		NIMB_ID:         table.NIMB_ID,
+		Engine:          table.Name,
+		Pipeline:        pipeline,
+		VariablePackage: table.VariablePackage,
+		Audit:           table.Audit,
+	}
+	if err := engine.Validate(engine.GenerateScript(eng)); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "Compiled decision table is not a valid script",
+			"details": err.Error(),
+		})
+		return
+	}
+	repo := repository.NewGenericRepository[models.WorkflowDef](c.Request.Context(), dts.mongo.Database, "engines")
+	if !eng.Audit.IsArchived {
+		// An engine of another minor version would otherwise still match the version number
+		_, err = repo.UpdateMany(
+			bson.M{"nimb_id": eng.NIMB_ID, "audit.version": eng.Audit.Version, "audit.minor_version": bson.M{"$ne": eng.Audit.MinorVersion}, "audit.is_archived": false},
+			bson.M{"$set": bson.M{"audit.is_archived": true}})
+		if HandleError(c, err, "Failed to archive earlier compiled decision table") {
+			return
+		}
+	}
+	_, err = repo.UpdateOne(
+		bson.M{"nimb_id": eng.NIMB_ID, "audit.version": eng.Audit.Version, "audit.minor_version": eng.Audit.MinorVersion},
+		bson.M{"$set": eng}, options.Update().SetUpsert(true))
+	if HandleError(c, err, "Failed to save compiled decision table") {
+		return
+	}
+	// A recompiled version must not be served from a script cached before
+	if err := dts.redis.Delete(c.Request.Context(), engineScriptKey(&eng)); err != nil {
+		slog.Warn("Failed to evict cached engine script", "nimb_id", eng.NIMB_ID, "error", err)
+	}
+	RespondJSON(c, 200, "success", "Decision table compiled", eng)
+}
+
+// findTable loads a decision table by version number or alias; a version number selects the
+// current minor version
+func (dts *DecisionTableService) findTable(ctx context.Context, nimbID, ref string) (*models.DecisionTable, error) {
+	pointer := models.VersionPointer{}
+	if version, err := strconv.Atoi(ref); err == nil {
+		pointer.Version = version
+	} else {
+		if pointer, err = resolveAlias(ctx, dts.mongo.Database, "decision_table", nimbID, ref); err != nil {
+			return nil, err
+		}
+	}
+	repo := repository.NewGenericRepository[models.DecisionTable](ctx, dts.mongo.Database, "decision_tables")
+	table, err := repo.FindOne(versionFilter(nimbID, pointer.Version, pointer.MinorVersion))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch decision table %s v%d: %w", nimbID, pointer.Version, err)
+	}
+	return table, nil
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/repository"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	executionHistoryCollection = "execution_history"
+	defaultHistoryPageSize     = 50
+	maxHistoryPageSize         = 500
+)
+
+// ensureHistoryIndexes creates the TTL index that expires execution records and the lookup indexes
+func (lfs *ExecutionService) ensureHistoryIndexes() {
+	if !lfs.cfg.Execution.History.Enabled {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	repo := repository.NewGenericRepository[models.ExecutionRecord](ctx, lfs.mongo.Database, executionHistoryCollection)
+	ensureTTLIndex(repo, "executed_at", lfs.cfg.Execution.History.TTLDays)
+	indexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "execution_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "nimb_id", Value: 1}, {Key: "executed_at", Value: -1}}},
+		{Keys: bson.D{{Key: "correlation_id", Value: 1}}},
+	}
+	for _, index := range indexes {
+		if _, err := repo.CreateIndex(index); err != nil {
+			slog.Error("Failed to create execution history index", "keys", index.Keys, "error", err)
+		}
+	}
+}
+
+// ensureTTLIndex expires the documents of the repository's collection ttlDays after their field.
+// The index is created when missing and its expiry updated when the setting changed since the
+// last start. A ttlDays of zero or less is rejected, as an expiry of zero deletes documents at once.
+func ensureTTLIndex[T any](repo *repository.GenericRepository[T], field string, ttlDays int) {
+	if ttlDays <= 0 {
+		slog.Error("Ignoring non-positive TTL, leaving the TTL index unchanged", "collection", repo.Coll.Name(), "ttl_days", ttlDays)
+		return
+	}
+	keys := bson.D{{Key: field, Value: 1}}
+	ttl := int32(ttlDays * 24 * 60 * 60)
+	indexes, err := repo.ListIndexes()
+	if err != nil {
+		slog.Error("Failed to list indexes", "collection", repo.Coll.Name(), "error", err)
+		return
+	}
+	for _, index := range indexes {
+		key, _ := index["key"].(bson.M)
+		if len(key) != 1 || key[field] == nil {
+			continue
+		}
+		if current, ok := index["expireAfterSeconds"]; ok && fmt.Sprint(current) == fmt.Sprint(ttl) {
+			return
+		}
+		if err := repo.SetIndexExpiry(keys, ttl); err != nil {
+			slog.Error("Failed to update TTL index", "collection", repo.Coll.Name(), "field", field, "error", err)
+			return
+		}
+		slog.Info("Updated TTL index", "collection", repo.Coll.Name(), "field", field, "ttl_days", ttlDays)
+		return
+	}
+	if _, err := repo.CreateIndex(mongo.IndexModel{Keys: keys, Options: options.Index().SetExpireAfterSeconds(ttl)}); err != nil {
+		slog.Error("Failed to create TTL index", "collection", repo.Coll.Name(), "field", field, "error", err)
+	}
+}
+
+// recordExecution writes the execution record and returns its ID, or "" when history is disabled
+// or the write failed. A failed write is logged and never fails the execution itself.
+func (lfs *ExecutionService) recordExecution(ctx context.Context, meta models.ExecutionMeta, result *models.ExecutionResult, inputJSON []byte, execErr error) string {
+	if !lfs.cfg.Execution.History.Enabled {
+		return ""
+	}
+	hash := sha256.Sum256(inputJSON)
+	record := models.ExecutionRecord{
+		ExecutionID:   utils.GenerateNIMBID("N_EXEC"),
+		NIMB_ID:       result.NIMB_ID,
+		Version:       result.Version,
+		MinorVersion:  result.MinorVersion,
+		Caller:        meta.Caller,
+		OrgID:         meta.OrgID,
+		CorrelationID: meta.CorrelationID,
+		Channel:       meta.Channel,
+		Route:         meta.Route,
+		InputHash:     hex.EncodeToString(hash[:]),
+		Output:        result.Data,
+		Trace:         result.Logs,
+		Outcome:       "success",
+		DurationMs:    result.Duration.Milliseconds(),
+		ExecutedAt:    time.Now(),
+	}
+	if lfs.cfg.Execution.History.StoreInput {
+		_ = json.Unmarshal(inputJSON, &record.Input)
+	}
+	if execErr != nil {
+		record.Outcome = "failure"
+		record.Error = execErr.Error()
+	}
+	// The caller's deadline may already be spent by the execution, so the write gets its own
+	writeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	repo := repository.NewGenericRepository[models.ExecutionRecord](writeCtx, lfs.mongo.Database, executionHistoryCollection)
+	if _, err := repo.InsertOne(record); err != nil {
+		slog.Error("Failed to record execution", "nimb_id", record.NIMB_ID, "error", err)
+		return ""
+	}
+	return record.ExecutionID
+}
+
+// requestMeta reads the caller identity and correlation ID from the request headers
+func requestMeta(c *gin.Context, channel string) models.ExecutionMeta {
+	return models.ExecutionMeta{
+		Caller:        c.GetHeader("user_id"),
+		OrgID:         c.GetHeader("org_id"),
+		CorrelationID: c.GetHeader("X-Correlation-ID"),
+		Channel:       channel,
+	}
+}
+
+// historyFilter builds the Mongo filter for the caller's org from the list filters
+func historyFilter(orgID string, f models.ExecutionHistoryFilter) bson.M {
+	filter := bson.M{"org_id": orgID}
+	if f.NIMB_ID != "" {
+		filter["nimb_id"] = f.NIMB_ID
+	}
+	if f.Version != 0 {
+		filter["version"] = f.Version
+	}
+	if f.Outcome != "" {
+		filter["outcome"] = f.Outcome
+	}
+	if f.CorrelationID != "" {
+		filter["correlation_id"] = f.CorrelationID
+	}
+	if f.From != nil || f.To != nil {
+		executedAt := bson.M{}
+		if f.From != nil {
+			executedAt["$gte"] = *f.From
+		}
+		if f.To != nil {
+			executedAt["$lte"] = *f.To
+		}
+		filter["executed_at"] = executedAt
+	}
+	return filter
+}
+
+// GetAllExecutions lists execution records of the caller's org, newest first, without input, output and trace
+func (lfs *ExecutionService) GetAllExecutions(c *gin.Context) {
+	var payload models.ExecutionHistoryFilter
+	err := c.ShouldBindJSON(&payload)
+	if HandleError(c, err, "Unable to unmarshel payload") {
+		return
+	}
+	if payload.Page < 1 {
+		payload.Page = 1
+	}
+	if payload.PageSize < 1 {
+		payload.PageSize = defaultHistoryPageSize
+	}
+	if payload.PageSize > maxHistoryPageSize {
+		payload.PageSize = maxHistoryPageSize
+	}
+	filter := historyFilter(c.GetHeader("org_id"), payload)
+	repo := repository.NewGenericRepository[models.ExecutionRecord](c.Request.Context(), lfs.mongo.Database, executionHistoryCollection)
+	total, err := repo.CountDocuments(filter)
+	if HandleError(c, err, "Failed to count executions") {
+		return
+	}
+	option := options.Find().
+		SetSort(bson.D{{Key: "executed_at", Value: -1}}).
+		SetSkip(int64((payload.Page - 1) * payload.PageSize)).
+		SetLimit(int64(payload.PageSize)).
+		SetProjection(bson.M{"input": 0, "output": 0, "trace": 0})
+	records, err := repo.FindMany(filter, option)
+	if HandleError(c, err, "Failed to fetch executions") {
+		return
+	}
+	c.JSON(200, models.ApiResponse{
+		Status:  "success",
+		Message: "Executions retrieved",
+		Data:    records,
+		Pagination: models.Pagination{
+			TotalRecords: total,
+			TotalPages:   int((total + int64(payload.PageSize) - 1) / int64(payload.PageSize)),
+			CurrentPage:  payload.Page,
+			PageSize:     payload.PageSize,
+		},
+	})
+}
+
+// GetExecutionByID returns one full execution record of the caller's org
+func (lfs *ExecutionService) GetExecutionByID(c *gin.Context) {
+	repo := repository.NewGenericRepository[models.ExecutionRecord](c.Request.Context(), lfs.mongo.Database, executionHistoryCollection)
+	record, err := repo.FindOne(bson.M{"execution_id": c.Query("execution_id"), "org_id": c.GetHeader("org_id")})
+	if HandleError(c, err, "Failed to fetch execution") {
+		return
+	}
+	record.Input = utils.NormalizeDocument(record.Input)
+	record.Output = utils.NormalizeDocument(record.Output)
+	RespondJSON(c, 200, "success", "Execution retrieved", record)
+}
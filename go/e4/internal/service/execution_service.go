@@ -1,6 +1,11 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"time"
@@ -11,26 +16,33 @@ import (
 	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
 	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/repository"
 	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/utils"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/pkg/cache"
 	"github.com/prithvirajv06/nimbus-uta/go/engine/pkg/database"
 	"github.com/prithvirajv06/nimbus-uta/go/engine/pkg/messaging"
-	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
 type ExecutionService struct {
 	mongo    *database.MongoDB
 	rabbitMQ *messaging.RabbitMQ
-	redis    *redis.Client
+	redis    *cache.RedisClient
 	cfg      *config.Config
+	// bounds the challenger runs in flight, see shadowExecute
+	shadowSlots chan struct{}
 }
 
-func NewExecutionService(db *database.MongoDB, rabbitMQ *messaging.RabbitMQ, cfg *config.Config) *ExecutionService {
+func NewExecutionService(db *database.MongoDB, redisClient *cache.RedisClient, rabbitMQ *messaging.RabbitMQ, cfg *config.Config) *ExecutionService {
 	rabbitMQ.DeclareQueue("variablepackage.event", true)
-	return &ExecutionService{
-		mongo:    db,
-		rabbitMQ: rabbitMQ,
-		cfg:      cfg,
+	svc := &ExecutionService{
+		mongo:       db,
+		redis:       redisClient,
+		rabbitMQ:    rabbitMQ,
+		cfg:         cfg,
+		shadowSlots: make(chan struct{}, max(cfg.Execution.ShadowWorkers, 1)),
 	}
+	svc.ensureHistoryIndexes()
+	svc.ensureShadowIndexes()
+	return svc
 }
 
 func (lfs *ExecutionService) CreateNewEngine(c *gin.Context) {
@@ -72,10 +84,15 @@ func (lfs *ExecutionService) UpdateEngine(c *gin.Context) {
 
 func (lfs *ExecutionService) GetEngineByNIMBID(c *gin.Context) {
 	nimbID := c.Query("nimb_id")
-	versionStr := c.Query("version")
-	version, _ := strconv.Atoi(versionStr)
-	repo := repository.NewGenericRepository[models.WorkflowDef](c.Request.Context(), lfs.mongo.Database, "engines")
-	engineFromDb, err := repo.FindOne(map[string]interface{}{"nimb_id": nimbID, "audit.is_archived": false, "audit.version": version})
+	// version takes a number or an alias; minor_version picks an exact, possibly archived, minor version
+	pointer, _, err := lfs.resolveEngineRef(c.Request.Context(), nimbID, c.Query("version"))
+	if HandleError(c, err, "Failed to resolve logic flow version") {
+		return
+	}
+	if minorVersion, err := strconv.Atoi(c.Query("minor_version")); err == nil {
+		pointer.MinorVersion = minorVersion
+	}
+	engineFromDb, err := lfs.findEngineVersion(c.Request.Context(), nimbID, pointer)
 	if HandleError(c, err, "Failed to fetch logic flow") {
 		return
 	}
@@ -122,13 +139,6 @@ func (lfs *ExecutionService) CloneEngine(c *gin.Context) {
 
 func (lfs *ExecutionService) HandleRuleExecution(c *gin.Context) {
 	nimbID := c.Query("nimb_id")
-	versionStr := c.Query("version")
-	version, _ := strconv.Atoi(versionStr)
-	repo := repository.NewGenericRepository[models.WorkflowDef](c.Request.Context(), lfs.mongo.Database, "engines")
-	eng, err := repo.FindOne(map[string]interface{}{"nimb_id": nimbID, "audit.is_archived": false, "audit.version": version})
-	if HandleError(c, err, "Failed to fetch logic flow for execution") {
-		return
-	}
 	var req map[string]interface{}
 
 	// 1. Parse the incoming JSON Facts (the data to be tested)
@@ -137,25 +147,49 @@ func (lfs *ExecutionService) HandleRuleExecution(c *gin.Context) {
 		return
 	}
 
-	// 3. Generate/Get Compiled Script
-	// In production, we cache the generated jsCode string based on a version hash
-	var redisCacheKey = "engine_script_" + eng.NIMB_ID + "_v" + strconv.Itoa(eng.Audit.Version)
-	var jsCode string
-	cached, err := lfs.redis.Get(c.Request.Context(), redisCacheKey).Result()
-	if err == nil {
-		jsCode = cached
+	// version takes a number or an alias; without one the engine's traffic split picks one, possibly from the facts
+	meta := requestMeta(c, "rest")
+	pointer, route, err := lfs.resolveVersion(c.Request.Context(), meta.OrgID, nimbID, c.Query("version"), req)
+	if HandleError(c, err, "Failed to resolve engine version for execution") {
+		return
 	}
-	if err != nil || cached == "" {
-		jsCode = engine.GenerateScript(*eng)
-		// Cache for future use
-		_ = lfs.redis.Set(c.Request.Context(), redisCacheKey, jsCode, 10*time.Minute)
+	meta.Route = route
+	eng, err := lfs.findEngineVersion(c.Request.Context(), nimbID, pointer)
+	if HandleError(c, err, "Failed to fetch logic flow for execution") {
+		return
 	}
+	c.Header("X-NIMBUS-VERSION", strconv.Itoa(eng.Audit.Version))
+	c.Header("X-NIMBUS-MINOR-VERSION", strconv.Itoa(eng.Audit.MinorVersion))
+	c.Header("X-NIMBUS-ROUTE", meta.Route)
 
-	// 4. Execute in the Sandboxed Runtime
-	start := time.Now()
-	finalData, logs, err := engine.Execute(jsCode, req)
-	duration := time.Since(start)
+	// 2. Keep a copy of the facts to diff the engine's output against in debug mode
+	debug := c.GetHeader("X-NIMBUS-DEBUG") == "YES"
+	var before map[string]interface{}
+	if debug {
+		before = utils.NormalizeDocument(req)
+	}
 
+	// 3-4. Generate/Get Compiled Script and Execute in the Sandboxed Runtime
+	result, err := lfs.execute(c.Request.Context(), eng, meta, req)
+	var invalid *InputValidationError
+	if errors.As(err, &invalid) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Input validation failed",
+			"details": invalid.Fields,
+		})
+		return
+	}
+	if result.ExecutionID != "" {
+		c.Header("X-NIMBUS-EXECUTION-ID", result.ExecutionID)
+	}
+	var violated *OutputContractError
+	if errors.As(err, &violated) {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Output contract violated",
+			"details": violated.Fields,
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Execution failed",
@@ -165,14 +199,132 @@ func (lfs *ExecutionService) HandleRuleExecution(c *gin.Context) {
 	}
 
 	// 5. Return the modified facts and audit info
-	c.Header("X_TIME-TAKEN", strconv.FormatInt(duration.Milliseconds(), 10))
-	if c.GetHeader("X-NIMBUS-DEBUG") == "YES" {
+	c.Header("X_TIME-TAKEN", strconv.FormatInt(result.Duration.Milliseconds(), 10))
+	if debug {
+		changes := utils.DiffJSON(before, result.Raw)
 		c.JSON(http.StatusOK, gin.H{
-			"data": finalData,
-			"logs": logs,
+			"data":    result.Data,
+			"logs":    result.Logs,
+			"patch":   utils.JSONPatch(changes),
+			"changes": utils.DescribeChanges(changes),
 		})
 		return
 	} else {
-		c.JSON(http.StatusOK, finalData)
+		c.JSON(http.StatusOK, result.Data)
+	}
+}
+
+// Invoke runs one fact document through the engine version that ref names, a version number or
+// an alias, or the one its traffic split picks when ref is empty, bounded by ctx.
+// Lookup errors wrap the repository error, so callers can tell a missing engine apart.
+func (lfs *ExecutionService) Invoke(ctx context.Context, meta models.ExecutionMeta, nimbID, ref string, facts map[string]interface{}) (*models.ExecutionResult, error) {
+	pointer, route, err := lfs.resolveVersion(ctx, meta.OrgID, nimbID, ref, facts)
+	if err != nil {
+		return nil, err
+	}
+	meta.Route = route
+	eng, err := lfs.findEngineVersion(ctx, nimbID, pointer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch engine %s v%d.%d: %w", nimbID, pointer.Version, pointer.MinorVersion, err)
+	}
+	return lfs.execute(ctx, eng, meta, facts)
+}
+
+// execute validates the facts against the engine's variable package, runs them through the loaded
+// engine, projects the output onto its output contract and records the execution in history. A named
+// challenger version is shadowed on the same input in the background. Facts failing validation return
+// an *InputValidationError and no result; otherwise the result is returned even when the script fails,
+// so callers can report its execution ID.
+func (lfs *ExecutionService) execute(ctx context.Context, eng *models.WorkflowDef, meta models.ExecutionMeta, facts map[string]interface{}) (*models.ExecutionResult, error) {
+	if facts == nil {
+		facts = map[string]interface{}{}
+	}
+	if err := validateFacts(eng.VariablePackage, facts); err != nil {
+		return nil, err
+	}
+	// Snapshot the input before the VM mutates facts in place
+	inputJSON, _ := json.Marshal(facts)
+	jsCode := lfs.engineScript(ctx, eng)
+	start := time.Now()
+	finalData, logs, err := engine.ExecuteContext(ctx, jsCode, facts)
+	result := &models.ExecutionResult{
+		NIMB_ID:      eng.NIMB_ID,
+		Version:      eng.Audit.Version,
+		MinorVersion: eng.Audit.MinorVersion,
+		Raw:          finalData,
+		Logs:         logs,
+		Duration:     time.Since(start),
+	}
+	if err == nil {
+		result.Data, err = projectOutput(eng.OutputContract, finalData)
+	}
+	result.ExecutionID = lfs.recordExecution(ctx, meta, result, inputJSON, err)
+	if eng.ChallengerVersion != 0 && eng.ChallengerVersion != eng.Audit.Version {
+		lfs.shadowExecute(ctx, eng, meta, result, inputJSON, err)
+	}
+	return result, err
+}
+
+// ValidateEngine checks that the engine version a version number or alias refers to compiles
+// into a runnable script and returns the compilation errors, if any.
+func (lfs *ExecutionService) ValidateEngine(ctx context.Context, nimbID, ref string, minorVersion int) ([]string, error) {
+	eng, err := lfs.findEngineRef(ctx, nimbID, ref, minorVersion)
+	if err != nil {
+		return nil, err
+	}
+	var problems []string
+	if err := engine.Validate(engine.GenerateScript(*eng)); err != nil {
+		problems = append(problems, err.Error())
+	}
+	return problems, nil
+}
+
+// findEngine loads the active engine definition for the given NIMB_ID and version.
+func (lfs *ExecutionService) findEngine(ctx context.Context, nimbID string, version int) (*models.WorkflowDef, error) {
+	return lfs.findEngineVersion(ctx, nimbID, models.VersionPointer{Version: version})
+}
+
+// findEngineRef loads the engine version a version number or alias refers to. A non-zero minor
+// version selects that minor of a version number; an alias points at its own.
+func (lfs *ExecutionService) findEngineRef(ctx context.Context, nimbID, ref string, minorVersion int) (*models.WorkflowDef, error) {
+	pointer, _, err := lfs.resolveEngineRef(ctx, nimbID, ref)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := strconv.Atoi(ref); err == nil {
+		pointer.MinorVersion = minorVersion
+	}
+	eng, err := lfs.findEngineVersion(ctx, nimbID, pointer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch engine %s v%d.%d: %w", nimbID, pointer.Version, pointer.MinorVersion, err)
+	}
+	return eng, nil
+}
+
+// findEngineVersion loads an engine definition by version and minor version, where a zero minor
+// version means the active one
+func (lfs *ExecutionService) findEngineVersion(ctx context.Context, nimbID string, pointer models.VersionPointer) (*models.WorkflowDef, error) {
+	repo := repository.NewGenericRepository[models.WorkflowDef](ctx, lfs.mongo.Database, "engines")
+	return repo.FindOne(versionFilter(nimbID, pointer.Version, pointer.MinorVersion))
+}
+
+// engineScriptKey is the cache key of an engine version's compiled script
+func engineScriptKey(eng *models.WorkflowDef) string {
+	return "engine_script_" + eng.NIMB_ID + "_v" + strconv.Itoa(eng.Audit.Version) + "." + strconv.Itoa(eng.Audit.MinorVersion)
+}
+
+// engineScript returns the compiled script for an engine, generating and caching it on a miss.
+// In production, we cache the generated jsCode string based on a version hash
+func (lfs *ExecutionService) engineScript(ctx context.Context, eng *models.WorkflowDef) string {
+	var redisCacheKey = engineScriptKey(eng)
+	var jsCode string
+	if err := lfs.redis.Get(ctx, redisCacheKey, &jsCode); err == nil && jsCode != "" {
+		return jsCode
+	}
+	jsCode = engine.GenerateScript(*eng)
+	// Cache for future use
+	if err := lfs.redis.Set(ctx, redisCacheKey, jsCode, 10*time.Minute); err != nil {
+		slog.Warn("Failed to cache engine script", "nimb_id", eng.NIMB_ID, "error", err)
 	}
+	return jsCode
 }
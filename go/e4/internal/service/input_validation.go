@@ -0,0 +1,111 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+)
+
+// Root of every variable key in a variable package, e.g. data.applicant.score
+const factsRoot = "data"
+
+// InputValidationError rejects facts that do not match the engine's variable package
+type InputValidationError struct {
+	Fields []models.FieldError
+}
+
+func (e *InputValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Field + ": " + field.Message
+	}
+	return "invalid input: " + strings.Join(messages, "; ")
+}
+
+// validateFacts checks the facts against the package's variables: required fields must be present
+// and non-null, values must match the declared type, and objects and arrays of objects are checked
+// against their children. An empty package declares no contract and accepts anything.
+func validateFacts(pkg models.VariablePackage, facts map[string]interface{}) error {
+	if len(pkg.Variables) == 0 {
+		return nil
+	}
+	var fields []models.FieldError
+	validateObject(pkg.Variables, factsRoot, factsRoot, facts, &fields)
+	if len(fields) > 0 {
+		return &InputValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// validateObject checks the variables declared under keyPrefix against obj, reporting fields under path.
+// keyPrefix is the declared key of the parent (with [*] for array elements), path the concrete one.
+func validateObject(variables []models.Variables, keyPrefix, path string, obj map[string]interface{}, fields *[]models.FieldError) {
+	for _, variable := range variables {
+		name := strings.TrimPrefix(variable.VarKey, keyPrefix+".")
+		fieldPath := path + "." + name
+		value, present := obj[name]
+		if !present || value == nil {
+			if variable.IsRequired && variable.Type != "null" {
+				*fields = append(*fields, models.FieldError{Field: fieldPath, Message: "is required"})
+			}
+			continue
+		}
+		if !matchesType(variable.Type, value) {
+			*fields = append(*fields, models.FieldError{
+				Field:   fieldPath,
+				Message: fmt.Sprintf("must be of type %s, got %s", variable.Type, jsonType(value)),
+			})
+			continue
+		}
+		if len(variable.Children) == 0 {
+			continue
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			validateObject(variable.Children, variable.VarKey, fieldPath, v, fields)
+		case []interface{}:
+			for i, elem := range v {
+				elemPath := fieldPath + "[" + strconv.Itoa(i) + "]"
+				doc, ok := elem.(map[string]interface{})
+				if !ok {
+					*fields = append(*fields, models.FieldError{Field: elemPath, Message: "must be of type object, got " + jsonType(elem)})
+					continue
+				}
+				validateObject(variable.Children, variable.VarKey+"[*]", elemPath, doc, fields)
+			}
+		}
+	}
+}
+
+// matchesType reports whether a decoded JSON value has the declared variable type.
+// Types the package could not infer (null, unknown) accept any value.
+func matchesType(declared string, value interface{}) bool {
+	switch declared {
+	case "string", "number", "boolean", "object", "array":
+		return jsonType(value) == declared
+	default:
+		return true
+	}
+}
+
+// jsonType names the JSON type of a decoded value, using the variable package's type names
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case float64, float32, int, int32, int64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/config"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/engine"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/repository"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/utils"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/pkg/database"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/pkg/dtable"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/pkg/logicflow"
+)
+
+// LogicFlowService executes the logic flows the core service stores
+type LogicFlowService struct {
+	mongo *database.MongoDB
+	cfg   *config.Config
+}
+
+func NewLogicFlowService(db *database.MongoDB, cfg *config.Config) *LogicFlowService {
+	return &LogicFlowService{
+		mongo: db,
+		cfg:   cfg,
+	}
+}
+
+// HandleLogicFlowExecution runs the steps of a logic flow version on one fact document and returns
+// the updated facts. Relative date comparisons count back from the evaluation_date query
+// parameter, or now. In debug mode it adds the branch each step took and a trace.
+func (lfs *LogicFlowService) HandleLogicFlowExecution(c *gin.Context) {
+	nimbID := c.Query("nimb_id")
+	var req map[string]interface{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+	at, responded := evaluationDate(c)
+	if responded {
+		return
+	}
+	flow, err := lfs.findFlow(c.Request.Context(), nimbID, c.Query("version"))
+	if HandleError(c, err, "Failed to fetch logic flow for execution") {
+		return
+	}
+
+	debug := c.GetHeader("X-NIMBUS-DEBUG") == "YES"
+	start := time.Now()
+	result, err := logicflow.ExecuteAt(c.Request.Context(), *flow, req, at, debug)
+	c.Header("X_TIME-TAKEN", strconv.FormatInt(time.Since(start).Milliseconds(), 10))
+	if err != nil {
+		response := gin.H{
+			"error":   "Execution failed",
+			"details": err.Error(),
+		}
+		if debug {
+			response["steps"] = result.Steps
+			response["trace"] = result.Trace
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	if debug {
+		c.JSON(http.StatusOK, gin.H{
+			"data":            result.Data,
+			"steps":           result.Steps,
+			"evaluation_date": dtable.FormatDate(at),
+			"trace":           result.Trace,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, result.Data)
+}
+
+// ConvertLogicFlow converts a logic flow version into the pipeline of a new engine, which behaves
+// as the flow when invoked, and saves it as the first version of that engine. Flows with a
+// comparison, group operator or operation the executor does not know are answered with 422 and
+// the reason.
+func (lfs *LogicFlowService) ConvertLogicFlow(c *gin.Context) {
+	flow, err := lfs.findFlow(c.Request.Context(), c.Query("nimb_id"), c.Query("version"))
+	if HandleError(c, err, "Failed to fetch logic flow for conversion") {
+		return
+	}
+	pipeline, err := logicflow.ToPipeline(*flow)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "Logic flow cannot be converted",
+			"details": err.Error(),
+		})
+		return
+	}
+	eng := models.WorkflowDef{
+		NIMB_ID:         utils.GenerateNIMBID("N_L_ENG_"),
+		Engine:          flow.Name,
+		Pipeline:        pipeline,
+		VariablePackage: flow.VariablePackage,
+	}
+	if err := engine.Validate(engine.GenerateScript(eng)); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "Converted logic flow is not a valid script",
+			"details": err.Error(),
+		})
+		return
+	}
+	eng.Audit.SetInitialAudit(c)
+	eng.Audit.Version, _ = GetNextVersionNumber(c, lfs.mongo.Database, eng.NIMB_ID)
+	repo := repository.NewGenericRepository[models.WorkflowDef](c.Request.Context(), lfs.mongo.Database, "engines")
+	_, err = repo.InsertOne(eng)
+	if HandleError(c, err, "Failed to save converted logic flow") {
+		return
+	}
+	RespondJSON(c, 201, "success", "Engine created from logic flow", eng)
+}
+
+// findFlow loads a logic flow version by number or by alias
+func (lfs *LogicFlowService) findFlow(ctx context.Context, nimbID, ref string) (*models.LogicFlow, error) {
+	pointer := models.VersionPointer{}
+	if version, err := strconv.Atoi(ref); err == nil {
+		pointer.Version = version
+	} else {
+		if pointer, err = resolveAlias(ctx, lfs.mongo.Database, "logic_flow", nimbID, ref); err != nil {
+			return nil, err
+		}
+	}
+	repo := repository.NewGenericRepository[models.LogicFlow](ctx, lfs.mongo.Database, "logic_flows")
+	flow, err := repo.FindOne(versionFilter(nimbID, pointer.Version, pointer.MinorVersion))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch logic flow %s v%d: %w", nimbID, pointer.Version, err)
+	}
+	return flow, nil
+}
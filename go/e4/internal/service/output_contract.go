@@ -0,0 +1,61 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+)
+
+// OutputContractError reports engine output that does not satisfy the engine's declared output contract
+type OutputContractError struct {
+	Fields []models.FieldError
+}
+
+func (e *OutputContractError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Field + ": " + field.Message
+	}
+	return "output contract violated: " + strings.Join(messages, "; ")
+}
+
+// projectOutput type-checks the engine output against the declared output fields and returns only
+// those fields, so scratch variables and unrelated input never reach the caller. An empty contract
+// returns the output unchanged.
+func projectOutput(contract []models.Variables, data map[string]interface{}) (map[string]interface{}, error) {
+	if len(contract) == 0 {
+		return data, nil
+	}
+	var fields []models.FieldError
+	validateObject(contract, factsRoot, factsRoot, data, &fields)
+	if len(fields) > 0 {
+		return nil, &OutputContractError{Fields: fields}
+	}
+	return projectObject(contract, factsRoot, data), nil
+}
+
+func projectObject(variables []models.Variables, keyPrefix string, obj map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(variables))
+	for _, variable := range variables {
+		name := strings.TrimPrefix(variable.VarKey, keyPrefix+".")
+		value, present := obj[name]
+		if !present {
+			continue
+		}
+		if len(variable.Children) > 0 {
+			switch v := value.(type) {
+			case map[string]interface{}:
+				value = projectObject(variable.Children, variable.VarKey, v)
+			case []interface{}:
+				elems := make([]interface{}, len(v))
+				for i, elem := range v {
+					// validateObject has already checked every element is an object
+					elems[i] = projectObject(variable.Children, variable.VarKey+"[*]", elem.(map[string]interface{}))
+				}
+				value = elems
+			}
+		}
+		out[name] = value
+	}
+	return out
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/repository"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultReplayLimit = 1000
+	maxReplayLimit     = 10000
+)
+
+// HandleReplay re-runs stored executions against a candidate engine version and reports, per record
+// and in aggregate, how the candidate's output differs from what was decided at the time.
+func (lfs *ExecutionService) HandleReplay(c *gin.Context) {
+	var payload models.ReplayRequest
+	if err := c.ShouldBindJSON(&payload); err != nil || payload.NIMB_ID == "" || payload.CandidateVersion == 0 {
+		RespondJSON(c, 400, "failure", "nimb_id and candidate_version are required", nil)
+		return
+	}
+	report, err := lfs.Replay(c.Request.Context(), c.GetHeader("org_id"), payload)
+	if HandleError(c, err, "Failed to replay executions") {
+		return
+	}
+	RespondJSON(c, 200, "success", "Replay completed", report)
+}
+
+// Replay runs the selected executions of the caller's org through the candidate version.
+// Executions stored without their input cannot be replayed and are reported as skipped.
+func (lfs *ExecutionService) Replay(ctx context.Context, orgID string, req models.ReplayRequest) (*models.ReplayReport, error) {
+	start := time.Now()
+	candidate, err := lfs.findEngine(ctx, req.NIMB_ID, req.CandidateVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch candidate engine %s v%d: %w", req.NIMB_ID, req.CandidateVersion, err)
+	}
+	records, err := lfs.replaySelection(ctx, orgID, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch executions to replay: %w", err)
+	}
+
+	report := &models.ReplayReport{
+		NIMB_ID:          req.NIMB_ID,
+		CandidateVersion: req.CandidateVersion,
+		Summary:          models.ReplaySummary{Total: len(records), FieldChanges: map[string]int{}},
+		Records:          []models.ReplayRecord{},
+	}
+	replayable := make([]models.ExecutionRecord, 0, len(records))
+	for _, rec := range records {
+		if rec.Input == nil {
+			report.Summary.Skipped++
+			report.Records = append(report.Records, models.ReplayRecord{
+				ExecutionID:     rec.ExecutionID,
+				BaselineVersion: rec.Version,
+				BaselineOutcome: rec.Outcome,
+				Status:          "skipped",
+				Error:           "execution was stored without its input",
+			})
+			continue
+		}
+		replayable = append(replayable, rec)
+	}
+
+	produce := func(dispatch func(batchRecord) bool) {
+		for index, rec := range replayable {
+			if !dispatch(batchRecord{index: index, facts: utils.NormalizeDocument(rec.Input)}) {
+				return
+			}
+		}
+	}
+	jsCode := lfs.engineScript(ctx, candidate)
+	executeBatch(ctx, candidate, jsCode, lfs.batchWorkers(""), false, produce, func(res models.BatchExecutionResult) {
+		out := compareReplay(replayable[res.Index], res)
+		report.Summary.Replayed++
+		switch out.Status {
+		case "changed":
+			report.Summary.Changed++
+		case "unchanged":
+			report.Summary.Unchanged++
+		case "failed":
+			report.Summary.Failed++
+		}
+		if out.BaselineOutcome != out.CandidateOutcome {
+			report.Summary.OutcomeFlips++
+		}
+		for _, change := range out.Changes {
+			report.Summary.FieldChanges[change.Path]++
+		}
+		if out.Status != "unchanged" || req.IncludeUnchanged {
+			report.Records = append(report.Records, out)
+		}
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if report.Summary.Replayed > 0 {
+		report.Summary.ChangeRate = float64(report.Summary.Changed) / float64(report.Summary.Replayed)
+	}
+	report.Summary.TimeTakenMs = time.Since(start).Milliseconds()
+	slog.Info("Finished replay", "nimb_id", req.NIMB_ID, "candidate_version", req.CandidateVersion,
+		"replayed", report.Summary.Replayed, "changed", report.Summary.Changed, "failed", report.Summary.Failed)
+	return report, nil
+}
+
+// replaySelection loads the executions picked by ID or by filter, newest first, bounded by the limit
+func (lfs *ExecutionService) replaySelection(ctx context.Context, orgID string, req models.ReplayRequest) ([]models.ExecutionRecord, error) {
+	filter := historyFilter(orgID, req.Filter)
+	filter["nimb_id"] = req.NIMB_ID
+	if len(req.ExecutionIDs) > 0 {
+		filter["execution_id"] = bson.M{"$in": req.ExecutionIDs}
+	}
+	limit := req.Limit
+	if limit < 1 {
+		limit = defaultReplayLimit
+	}
+	if limit > maxReplayLimit {
+		limit = maxReplayLimit
+	}
+	repo := repository.NewGenericRepository[models.ExecutionRecord](ctx, lfs.mongo.Database, executionHistoryCollection)
+	option := options.Find().SetSort(bson.D{{Key: "executed_at", Value: -1}}).SetLimit(int64(limit))
+	return repo.FindMany(filter, option)
+}
+
+// compareReplay diffs the stored output of an execution against the candidate's output
+func compareReplay(baseline models.ExecutionRecord, candidate models.BatchExecutionResult) models.ReplayRecord {
+	out := models.ReplayRecord{
+		ExecutionID:      baseline.ExecutionID,
+		BaselineVersion:  baseline.Version,
+		BaselineOutcome:  baseline.Outcome,
+		CandidateOutcome: candidate.Status,
+	}
+	if candidate.Status != "success" {
+		out.Status = "failed"
+		out.Error = candidate.Error
+		return out
+	}
+	before := baseline.Output
+	if before == nil {
+		// A failed baseline decided nothing, so everything the candidate returns is a change
+		before = map[string]interface{}{}
+	}
+	out.Changes = utils.DiffJSON(before, candidate.Data)
+	out.Status = "unchanged"
+	if len(out.Changes) > 0 || baseline.Outcome != candidate.Status {
+		out.Status = "changed"
+	}
+	return out
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/engine"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/repository"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	shadowComparisonCollection = "shadow_comparisons"
+	// Upper bound for loading and running the challenger of one request
+	shadowExecutionTimeout = 30 * time.Second
+	// Changed fields listed per champion/challenger pair in the summary
+	shadowTopFields = 20
+)
+
+// ensureShadowIndexes creates the lookup index of the comparison collection and expires comparisons
+// with the execution history TTL
+func (lfs *ExecutionService) ensureShadowIndexes() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	repo := repository.NewGenericRepository[models.ShadowComparison](ctx, lfs.mongo.Database, shadowComparisonCollection)
+	ensureTTLIndex(repo, "compared_at", lfs.cfg.Execution.History.TTLDays)
+	indexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "nimb_id", Value: 1}, {Key: "champion_version", Value: 1}, {Key: "challenger_version", Value: 1}}},
+	}
+	for _, index := range indexes {
+		if _, err := repo.CreateIndex(index); err != nil {
+			slog.Error("Failed to create shadow comparison index", "keys", index.Keys, "error", err)
+		}
+	}
+}
+
+// shadowExecute runs the engine's challenger version on the same input in the background and
+// records how its output and latency compare with the champion's. The caller never waits on it:
+// when all shadow slots are busy the comparison is dropped rather than queued.
+func (lfs *ExecutionService) shadowExecute(ctx context.Context, champion *models.WorkflowDef, meta models.ExecutionMeta, result *models.ExecutionResult, inputJSON []byte, championErr error) {
+	select {
+	case lfs.shadowSlots <- struct{}{}:
+	default:
+		slog.Warn("Dropping challenger run, all shadow slots busy", "nimb_id", champion.NIMB_ID, "challenger_version", champion.ChallengerVersion)
+		return
+	}
+	comparison := models.ShadowComparison{
+		ComparisonID:      utils.GenerateNIMBID("N_SHDW"),
+		ExecutionID:       result.ExecutionID,
+		NIMB_ID:           champion.NIMB_ID,
+		OrgID:             meta.OrgID,
+		ChampionVersion:   champion.Audit.Version,
+		ChallengerVersion: champion.ChallengerVersion,
+		ChampionOutcome:   "success",
+		ChampionMs:        result.Duration.Milliseconds(),
+	}
+	if championErr != nil {
+		comparison.ChampionOutcome = "failure"
+	}
+	championData := result.Data
+	go func() {
+		defer func() { <-lfs.shadowSlots }()
+		shadowCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shadowExecutionTimeout)
+		defer cancel()
+		lfs.runChallenger(shadowCtx, &comparison, championData, inputJSON)
+		comparison.ComparedAt = time.Now()
+		repo := repository.NewGenericRepository[models.ShadowComparison](shadowCtx, lfs.mongo.Database, shadowComparisonCollection)
+		if _, err := repo.InsertOne(comparison); err != nil {
+			slog.Error("Failed to record shadow comparison", "nimb_id", comparison.NIMB_ID, "error", err)
+		}
+	}()
+}
+
+// runChallenger executes the challenger on a fresh copy of the input and fills in the comparison
+func (lfs *ExecutionService) runChallenger(ctx context.Context, comparison *models.ShadowComparison, championData map[string]interface{}, inputJSON []byte) {
+	comparison.ChallengerOutcome = "failure"
+	challenger, err := lfs.findEngine(ctx, comparison.NIMB_ID, comparison.ChallengerVersion)
+	if err != nil {
+		comparison.Error = "failed to fetch challenger: " + err.Error()
+		return
+	}
+	var facts map[string]interface{}
+	if err := json.Unmarshal(inputJSON, &facts); err != nil {
+		comparison.Error = "failed to decode input: " + err.Error()
+		return
+	}
+	if facts == nil {
+		facts = map[string]interface{}{}
+	}
+	if err := validateFacts(challenger.VariablePackage, facts); err != nil {
+		comparison.Error = err.Error()
+		return
+	}
+	jsCode := lfs.engineScript(ctx, challenger)
+	start := time.Now()
+	finalData, _, err := engine.ExecuteContext(ctx, jsCode, facts)
+	comparison.ChallengerMs = time.Since(start).Milliseconds()
+	if err == nil {
+		finalData, err = projectOutput(challenger.OutputContract, finalData)
+	}
+	if err != nil {
+		comparison.Error = err.Error()
+		comparison.Matched = comparison.ChampionOutcome == "failure"
+		return
+	}
+	comparison.ChallengerOutcome = "success"
+	if comparison.ChampionOutcome == "success" {
+		comparison.Changes = utils.DiffJSON(championData, finalData)
+		comparison.Matched = len(comparison.Changes) == 0
+	}
+}
+
+// GetShadowSummary aggregates the shadow comparisons of an engine per champion/challenger pair,
+// optionally narrowed to one champion version
+func (lfs *ExecutionService) GetShadowSummary(c *gin.Context) {
+	match := bson.M{"org_id": c.GetHeader("org_id"), "nimb_id": c.Query("nimb_id")}
+	if version, err := strconv.Atoi(c.Query("version")); err == nil {
+		match["champion_version"] = version
+	}
+	summaries, err := repository.NewGenericRepository[models.ShadowSummary](c.Request.Context(), lfs.mongo.Database, shadowComparisonCollection).
+		Aggregate(bson.A{
+			bson.M{"$match": match},
+			bson.M{"$group": bson.M{
+				"_id":                 bson.M{"champion": "$champion_version", "challenger": "$challenger_version"},
+				"total":               bson.M{"$sum": 1},
+				"matched":             bson.M{"$sum": bson.M{"$cond": bson.A{"$matched", 1, 0}}},
+				"challenger_failures": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$challenger_outcome", "failure"}}, 1, 0}}},
+				"avg_champion_ms":     bson.M{"$avg": "$champion_ms"},
+				"avg_challenger_ms":   bson.M{"$avg": "$challenger_ms"},
+				"max_champion_ms":     bson.M{"$max": "$champion_ms"},
+				"max_challenger_ms":   bson.M{"$max": "$challenger_ms"},
+				"first_compared_at":   bson.M{"$min": "$compared_at"},
+				"last_compared_at":    bson.M{"$max": "$compared_at"},
+			}},
+			bson.M{"$addFields": bson.M{
+				"champion_version":   "$_id.champion",
+				"challenger_version": "$_id.challenger",
+				"mismatched":         bson.M{"$subtract": bson.A{"$total", "$matched"}},
+				"match_rate":         bson.M{"$divide": bson.A{"$matched", "$total"}},
+			}},
+			bson.M{"$sort": bson.D{{Key: "champion_version", Value: -1}, {Key: "challenger_version", Value: -1}}},
+		})
+	if HandleError(c, err, "Failed to summarize shadow comparisons") {
+		return
+	}
+	fields, err := repository.NewGenericRepository[models.FieldChangeCount](c.Request.Context(), lfs.mongo.Database, shadowComparisonCollection).
+		Aggregate(bson.A{
+			bson.M{"$match": match},
+			bson.M{"$unwind": "$changes"},
+			bson.M{"$group": bson.M{
+				"_id":   bson.M{"champion": "$champion_version", "challenger": "$challenger_version", "path": "$changes.path"},
+				"count": bson.M{"$sum": 1},
+			}},
+			bson.M{"$project": bson.M{
+				"champion_version":   "$_id.champion",
+				"challenger_version": "$_id.challenger",
+				"path":               "$_id.path",
+				"count":              1,
+			}},
+			bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "path", Value: 1}}},
+		})
+	if HandleError(c, err, "Failed to summarize shadow comparisons") {
+		return
+	}
+	for i := range summaries {
+		summaries[i].TopChangedFields = []models.FieldChangeCount{}
+		for _, field := range fields {
+			if field.ChampionVersion == summaries[i].ChampionVersion && field.ChallengerVersion == summaries[i].ChallengerVersion &&
+				len(summaries[i].TopChangedFields) < shadowTopFields {
+				summaries[i].TopChangedFields = append(summaries[i].TopChangedFields, field)
+			}
+		}
+	}
+	RespondJSON(c, 200, "success", "Shadow summary retrieved", summaries)
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const trafficSplitCollection = "traffic_splits"
+
+// Route labels recorded with an execution, telling how its version was chosen
+const (
+	routePinned   = "pinned"
+	routeWeighted = "weighted"
+	routeSticky   = "sticky"
+	routeAlias    = "alias:" // followed by the alias name
+)
+
+// resolveVersion picks the engine version to serve a request. A version number or alias in ref
+// always wins; without one the engine's traffic split decides. It returns the version and the route label.
+func (lfs *ExecutionService) resolveVersion(ctx context.Context, orgID, nimbID, ref string, facts map[string]interface{}) (models.VersionPointer, string, error) {
+	if ref != "" {
+		return lfs.resolveEngineRef(ctx, nimbID, ref)
+	}
+	repo := repository.NewGenericRepository[models.TrafficSplit](ctx, lfs.mongo.Database, trafficSplitCollection)
+	split, err := repo.FindOne(bson.M{"nimb_id": nimbID, "org_id": orgID})
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return models.VersionPointer{}, "", fmt.Errorf("no version requested and no traffic split configured for %s: %w", nimbID, err)
+	}
+	if err != nil {
+		return models.VersionPointer{}, "", fmt.Errorf("failed to fetch traffic split for %s: %w", nimbID, err)
+	}
+	if split.Strategy == routeSticky {
+		if key, ok := lookupFact(facts, split.HashField); ok {
+			return models.VersionPointer{Version: pickRoute(split.Routes, stickyBucket(nimbID, key, totalWeight(split.Routes)))}, routeSticky, nil
+		}
+	}
+	// Weighted, or sticky without the hash field in the facts
+	return models.VersionPointer{Version: pickRoute(split.Routes, rand.IntN(totalWeight(split.Routes)))}, routeWeighted, nil
+}
+
+// resolveEngineRef resolves a version number, pinned to the current minor version, or an alias name
+func (lfs *ExecutionService) resolveEngineRef(ctx context.Context, nimbID, ref string) (models.VersionPointer, string, error) {
+	if version, err := strconv.Atoi(ref); err == nil {
+		return models.VersionPointer{Version: version}, routePinned, nil
+	}
+	pointer, err := resolveAlias(ctx, lfs.mongo.Database, "engine", nimbID, ref)
+	return pointer, routeAlias + ref, err
+}
+
+// VersionRef turns the version number and alias of a typed request into a version reference,
+// preferring the alias. It is empty when neither is set.
+func VersionRef(version int, alias string) string {
+	if alias != "" {
+		return alias
+	}
+	if version != 0 {
+		return strconv.Itoa(version)
+	}
+	return ""
+}
+
+func totalWeight(routes []models.TrafficRoute) int {
+	total := 0
+	for _, route := range routes {
+		total += route.Weight
+	}
+	return total
+}
+
+// pickRoute returns the version whose cumulative weight range holds bucket
+func pickRoute(routes []models.TrafficRoute, bucket int) int {
+	for _, route := range routes {
+		if bucket < route.Weight {
+			return route.Version
+		}
+		bucket -= route.Weight
+	}
+	return routes[len(routes)-1].Version
+}
+
+// stickyBucket hashes the key into [0, total). The engine ID is mixed in so that different
+// engines split the same customers independently.
+func stickyBucket(nimbID, key string, total int) int {
+	h := fnv.New32a()
+	h.Write([]byte(nimbID + ":" + key))
+	return int(h.Sum32() % uint32(total))
+}
+
+// lookupFact reads a dotted field path such as data.customer.id from the facts
+func lookupFact(facts map[string]interface{}, path string) (string, bool) {
+	var current interface{} = facts
+	for _, name := range strings.Split(strings.TrimPrefix(path, factsRoot+"."), ".") {
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		if current, ok = obj[name]; !ok || current == nil {
+			return "", false
+		}
+	}
+	return fmt.Sprint(current), true
+}
+
+// SaveTrafficSplit creates or replaces the traffic split of an engine. Every routed version must exist.
+func (lfs *ExecutionService) SaveTrafficSplit(c *gin.Context) {
+	var payload models.TrafficSplit
+	err := c.ShouldBindJSON(&payload)
+	if HandleError(c, err, "Unable to unmarshel payload") {
+		return
+	}
+	if problem := lfs.checkTrafficSplit(c.Request.Context(), payload); problem != "" {
+		RespondJSON(c, 400, "failure", problem, nil)
+		return
+	}
+	payload.OrgID = c.GetHeader("org_id")
+	payload.Audit.SetInitialAudit(c)
+	repo := repository.NewGenericRepository[models.TrafficSplit](c.Request.Context(), lfs.mongo.Database, trafficSplitCollection)
+	existing, err := repo.FindOne(bson.M{"nimb_id": payload.NIMB_ID, "org_id": payload.OrgID})
+	if err == nil {
+		payload.Audit.CreatedAt = existing.Audit.CreatedAt
+		payload.Audit.CreatedBy = existing.Audit.CreatedBy
+		payload.Audit.MinorVersion = existing.Audit.MinorVersion + 1
+	}
+	_, err = repo.UpdateOne(bson.M{"nimb_id": payload.NIMB_ID, "org_id": payload.OrgID}, bson.M{"$set": payload}, options.Update().SetUpsert(true))
+	if HandleError(c, err, "Failed to save traffic split") {
+		return
+	}
+	RespondJSON(c, 200, "success", "Traffic split saved", payload)
+}
+
+func (lfs *ExecutionService) checkTrafficSplit(ctx context.Context, split models.TrafficSplit) string {
+	if split.NIMB_ID == "" || len(split.Routes) == 0 {
+		return "nimb_id and at least one route are required"
+	}
+	switch split.Strategy {
+	case routeWeighted:
+	case routeSticky:
+		if split.HashField == "" {
+			return "hash_field is required for the sticky strategy"
+		}
+	default:
+		return "strategy must be weighted or sticky"
+	}
+	seen := map[int]bool{}
+	for _, route := range split.Routes {
+		if route.Weight <= 0 {
+			return fmt.Sprintf("route for version %d must have a positive weight", route.Version)
+		}
+		if seen[route.Version] {
+			return fmt.Sprintf("version %d is routed twice", route.Version)
+		}
+		seen[route.Version] = true
+		if _, err := lfs.findEngine(ctx, split.NIMB_ID, route.Version); err != nil {
+			return fmt.Sprintf("version %d of %s does not exist", route.Version, split.NIMB_ID)
+		}
+	}
+	return ""
+}
+
+func (lfs *ExecutionService) GetTrafficSplit(c *gin.Context) {
+	repo := repository.NewGenericRepository[models.TrafficSplit](c.Request.Context(), lfs.mongo.Database, trafficSplitCollection)
+	split, err := repo.FindOne(bson.M{"nimb_id": c.Query("nimb_id"), "org_id": c.GetHeader("org_id")})
+	if HandleError(c, err, "Failed to fetch traffic split") {
+		return
+	}
+	RespondJSON(c, 200, "success", "Traffic split retrieved", split)
+}
+
+// DeleteTrafficSplit removes the split, so invocations must name a version again
+func (lfs *ExecutionService) DeleteTrafficSplit(c *gin.Context) {
+	repo := repository.NewGenericRepository[models.TrafficSplit](c.Request.Context(), lfs.mongo.Database, trafficSplitCollection)
+	_, err := repo.DeleteOne(bson.M{"nimb_id": c.Query("nimb_id"), "org_id": c.GetHeader("org_id")})
+	if HandleError(c, err, "Failed to delete traffic split") {
+		return
+	}
+	RespondJSON(c, 200, "success", "Traffic split deleted", nil)
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/config"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/repository"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/pkg/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	versionAliasCollection        = "version_aliases"
+	versionAliasHistoryCollection = "version_alias_history"
+)
+
+// Collections of the entity types that can carry aliases
+var aliasEntityCollections = map[string]string{
+	"engine":         "engines",
+	"decision_table": "decision_tables",
+	"logic_flow":     "logic_flows",
+	"orchestration":  "orcestartions",
+}
+
+// Alias names are lower case and never all digits, so they cannot be mistaken for a version number
+var aliasNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,31}$`)
+
+// ErrAliasMoved is returned when an alias no longer points at the version the caller expected
+var ErrAliasMoved = errors.New("alias was repointed concurrently")
+
+type VersionAliasService struct {
+	mongo *database.MongoDB
+	cfg   *config.Config
+}
+
+func NewVersionAliasService(db *database.MongoDB, cfg *config.Config) *VersionAliasService {
+	svc := &VersionAliasService{
+		mongo: db,
+		cfg:   cfg,
+	}
+	svc.ensureAliasIndexes()
+	return svc
+}
+
+func (s *VersionAliasService) ensureAliasIndexes() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	aliases := repository.NewGenericRepository[models.VersionAlias](ctx, s.mongo.Database, versionAliasCollection)
+	_, err := aliases.CreateIndex(mongo.IndexModel{
+		Keys:    bson.D{{Key: "entity_type", Value: 1}, {Key: "nimb_id", Value: 1}, {Key: "alias", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		slog.Error("Failed to create version alias index", "error", err)
+	}
+	history := repository.NewGenericRepository[models.VersionAliasHistory](ctx, s.mongo.Database, versionAliasHistoryCollection)
+	_, err = history.CreateIndex(mongo.IndexModel{
+		Keys: bson.D{{Key: "entity_type", Value: 1}, {Key: "nimb_id", Value: 1}, {Key: "alias", Value: 1}, {Key: "changed_at", Value: -1}},
+	})
+	if err != nil {
+		slog.Error("Failed to create version alias history index", "error", err)
+	}
+}
+
+// RepointAlias creates an alias or moves it to another version in a single atomic update and
+// records the move in the alias history. With `expected` set it acts as a compare-and-swap.
+func (s *VersionAliasService) RepointAlias(c *gin.Context) {
+	var payload models.VersionAliasRequest
+	err := c.ShouldBindJSON(&payload)
+	if HandleError(c, err, "Unable to unmarshel payload") {
+		return
+	}
+	if problem := checkAliasRequest(payload); problem != "" {
+		RespondJSON(c, 400, "failure", problem, nil)
+		return
+	}
+	ctx := c.Request.Context()
+	target, err := findVersion(ctx, s.mongo.Database, payload.EntityType, payload.NIMB_ID, payload.Version, payload.MinorVersion)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		RespondJSON(c, 400, "failure", fmt.Sprintf("version %d.%d of %s does not exist", payload.Version, payload.MinorVersion, payload.NIMB_ID), nil)
+		return
+	}
+	if HandleError(c, err, "Failed to fetch alias target") {
+		return
+	}
+	to := models.VersionPointer{Version: target.Audit.Version, MinorVersion: target.Audit.MinorVersion}
+
+	userID := c.GetHeader("user_id")
+	if userID == "" {
+		userID = "SYSTEM"
+	}
+	now := time.Now()
+	filter := bson.M{"entity_type": payload.EntityType, "nimb_id": payload.NIMB_ID, "alias": payload.Alias}
+	option := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+	if payload.Expected != nil {
+		filter["version"] = payload.Expected.Version
+		filter["minor_version"] = payload.Expected.MinorVersion
+	} else {
+		option.SetUpsert(true)
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"version":           to.Version,
+			"minor_version":     to.MinorVersion,
+			"audit.modified_at": now,
+			"audit.modified_by": userID,
+		},
+		"$setOnInsert": bson.M{
+			"audit.created_at": now,
+			"audit.created_by": userID,
+		},
+	}
+	aliases := repository.NewGenericRepository[models.VersionAlias](ctx, s.mongo.Database, versionAliasCollection)
+	previous, err := aliases.FindOneAndUpdate(filter, update, option)
+	var from *models.VersionPointer
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments) && payload.Expected != nil:
+		RespondJSON(c, 409, "failure", ErrAliasMoved.Error(), nil)
+		return
+	case errors.Is(err, mongo.ErrNoDocuments):
+		// Upserted: the alias did not exist before
+	case HandleError(c, err, "Failed to repoint alias"):
+		return
+	default:
+		from = &models.VersionPointer{Version: previous.Version, MinorVersion: previous.MinorVersion}
+	}
+
+	history := repository.NewGenericRepository[models.VersionAliasHistory](ctx, s.mongo.Database, versionAliasHistoryCollection)
+	_, err = history.InsertOne(models.VersionAliasHistory{
+		EntityType: payload.EntityType,
+		NIMB_ID:    payload.NIMB_ID,
+		Alias:      payload.Alias,
+		From:       from,
+		To:         to,
+		Reason:     payload.Reason,
+		ChangedBy:  userID,
+		ChangedAt:  now,
+	})
+	if err != nil {
+		// The alias has already moved; a missing history entry must not report the move as failed
+		slog.Error("Failed to record alias history", "nimb_id", payload.NIMB_ID, "alias", payload.Alias, "error", err)
+	}
+	slog.Info("Repointed alias", "entity_type", payload.EntityType, "nimb_id", payload.NIMB_ID, "alias", payload.Alias,
+		"version", to.Version, "minor_version", to.MinorVersion)
+	RespondJSON(c, 200, "success", "Alias repointed", models.VersionAlias{
+		EntityType:   payload.EntityType,
+		NIMB_ID:      payload.NIMB_ID,
+		Alias:        payload.Alias,
+		Version:      to.Version,
+		MinorVersion: to.MinorVersion,
+	})
+}
+
+func checkAliasRequest(req models.VersionAliasRequest) string {
+	if _, ok := aliasEntityCollections[req.EntityType]; !ok {
+		return "entity_type must be one of engine, decision_table, logic_flow, orchestration"
+	}
+	if req.NIMB_ID == "" || req.Version < 1 {
+		return "nimb_id and version are required"
+	}
+	if !aliasNamePattern.MatchString(req.Alias) {
+		return "alias must start with a letter and contain only lower case letters, digits, '_' or '-'"
+	}
+	return ""
+}
+
+// GetAllAliases lists the aliases of one entity
+func (s *VersionAliasService) GetAllAliases(c *gin.Context) {
+	repo := repository.NewGenericRepository[models.VersionAlias](c.Request.Context(), s.mongo.Database, versionAliasCollection)
+	aliases, err := repo.FindMany(bson.M{"entity_type": c.Query("entity_type"), "nimb_id": c.Query("nimb_id")},
+		options.Find().SetSort(bson.D{{Key: "alias", Value: 1}}))
+	if HandleError(c, err, "Failed to fetch aliases") {
+		return
+	}
+	RespondJSON(c, 200, "success", "Aliases retrieved", aliases)
+}
+
+// GetAliasHistory lists the moves of an entity's aliases, newest first, optionally for one alias
+func (s *VersionAliasService) GetAliasHistory(c *gin.Context) {
+	filter := bson.M{"entity_type": c.Query("entity_type"), "nimb_id": c.Query("nimb_id")}
+	if alias := c.Query("alias"); alias != "" {
+		filter["alias"] = alias
+	}
+	repo := repository.NewGenericRepository[models.VersionAliasHistory](c.Request.Context(), s.mongo.Database, versionAliasHistoryCollection)
+	history, err := repo.FindMany(filter, options.Find().SetSort(bson.D{{Key: "changed_at", Value: -1}}))
+	if HandleError(c, err, "Failed to fetch alias history") {
+		return
+	}
+	RespondJSON(c, 200, "success", "Alias history retrieved", history)
+}
+
+// resolveAlias returns the version/minor-version pair an alias currently points at
+func resolveAlias(ctx context.Context, db *mongo.Database, entityType, nimbID, alias string) (models.VersionPointer, error) {
+	repo := repository.NewGenericRepository[models.VersionAlias](ctx, db, versionAliasCollection)
+	found, err := repo.FindOne(bson.M{"entity_type": entityType, "nimb_id": nimbID, "alias": alias})
+	if err != nil {
+		return models.VersionPointer{}, fmt.Errorf("failed to resolve alias %q of %s: %w", alias, nimbID, err)
+	}
+	return models.VersionPointer{Version: found.Version, MinorVersion: found.MinorVersion}, nil
+}
+
+// versionFilter selects one version of an entity. A zero minor version selects the current,
+// unarchived minor; an explicit one selects exactly that minor, archived or not.
+func versionFilter(nimbID string, version, minorVersion int) bson.M {
+	filter := bson.M{"nimb_id": nimbID, "audit.version": version}
+	if minorVersion == 0 {
+		filter["audit.is_archived"] = false
+	} else {
+		filter["audit.minor_version"] = minorVersion
+	}
+	return filter
+}
+
+// findVersion loads the versioning fields of one version of an entity
+func findVersion(ctx context.Context, db *mongo.Database, entityType, nimbID string, version, minorVersion int) (*models.VersionedEntity, error) {
+	repo := repository.NewGenericRepository[models.VersionedEntity](ctx, db, aliasEntityCollections[entityType])
+	return repo.FindOne(versionFilter(nimbID, version, minorVersion), options.FindOne().SetProjection(bson.M{"nimb_id": 1, "audit": 1}))
+}
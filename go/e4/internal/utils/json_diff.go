@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DiffJSON walks two JSON documents and returns the field level changes that turn before into after.
+// Paths are JSON pointers; removed array elements are listed from the highest index down.
+func DiffJSON(before, after interface{}) []models.FieldChange {
+	var changes []models.FieldChange
+	diffValue("", NormalizeJSON(before), NormalizeJSON(after), &changes)
+	return changes
+}
+
+func diffValue(path string, before, after interface{}, changes *[]models.FieldChange) {
+	switch b := before.(type) {
+	case map[string]interface{}:
+		a, ok := after.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(b)+len(a))
+		for k := range b {
+			keys = append(keys, k)
+		}
+		for k := range a {
+			if _, seen := b[k]; !seen {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			bv, inBefore := b[k]
+			av, inAfter := a[k]
+			child := path + "/" + escapePointer(k)
+			switch {
+			case !inAfter:
+				*changes = append(*changes, models.FieldChange{Op: "remove", Path: child, Before: bv})
+			case !inBefore:
+				*changes = append(*changes, models.FieldChange{Op: "add", Path: child, After: av})
+			default:
+				diffValue(child, bv, av, changes)
+			}
+		}
+		return
+	case []interface{}:
+		a, ok := after.([]interface{})
+		if !ok {
+			break
+		}
+		common := min(len(b), len(a))
+		for i := 0; i < common; i++ {
+			diffValue(path+"/"+strconv.Itoa(i), b[i], a[i], changes)
+		}
+		for i := common; i < len(a); i++ {
+			*changes = append(*changes, models.FieldChange{Op: "add", Path: path + "/" + strconv.Itoa(i), After: a[i]})
+		}
+		for i := len(b) - 1; i >= common; i-- {
+			*changes = append(*changes, models.FieldChange{Op: "remove", Path: path + "/" + strconv.Itoa(i), Before: b[i]})
+		}
+		return
+	}
+	if !reflect.DeepEqual(before, after) {
+		*changes = append(*changes, models.FieldChange{Op: "replace", Path: path, Before: before, After: after})
+	}
+}
+
+// escapePointer escapes a key as a JSON pointer reference token (RFC 6901)
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+// NormalizeJSON converts a document decoded from Mongo or produced by the runtime into plain
+// encoding/json values: nested documents become maps, arrays slices and every number a float64.
+func NormalizeJSON(v interface{}) interface{} {
+	raw, err := json.Marshal(plainBSON(v))
+	if err != nil {
+		return v
+	}
+	var out interface{}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return v
+	}
+	return out
+}
+
+// NormalizeDocument is NormalizeJSON for a top level document
+func NormalizeDocument(doc map[string]interface{}) map[string]interface{} {
+	if doc == nil {
+		return nil
+	}
+	out, _ := NormalizeJSON(doc).(map[string]interface{})
+	return out
+}
+
+// plainBSON unwraps the driver's ordered documents and arrays, which otherwise marshal as key/value lists
+func plainBSON(v interface{}) interface{} {
+	switch t := v.(type) {
+	case primitive.D:
+		m := make(map[string]interface{}, len(t))
+		for _, e := range t {
+			m[e.Key] = plainBSON(e.Value)
+		}
+		return m
+	case primitive.M:
+		return plainBSON(map[string]interface{}(t))
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			m[k] = plainBSON(e)
+		}
+		return m
+	case primitive.A:
+		return plainBSON([]interface{}(t))
+	case []interface{}:
+		s := make([]interface{}, len(t))
+		for i, e := range t {
+			s[i] = plainBSON(e)
+		}
+		return s
+	default:
+		return v
+	}
+}
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+)
+
+// JSONPatch turns field changes into RFC 6902 operations that, applied in order, rewrite the
+// original document into the changed one.
+func JSONPatch(changes []models.FieldChange) []models.JSONPatchOperation {
+	patch := make([]models.JSONPatchOperation, 0, len(changes))
+	for _, change := range changes {
+		op := models.JSONPatchOperation{Op: change.Op, Path: change.Path}
+		if change.Op != "remove" {
+			op.Value = change.After
+		}
+		patch = append(patch, op)
+	}
+	return patch
+}
+
+// DescribeChanges renders field changes as one readable line each, e.g. `changed applicant.score from 610 to 720`
+func DescribeChanges(changes []models.FieldChange) []string {
+	lines := make([]string, 0, len(changes))
+	for _, change := range changes {
+		field := pointerToField(change.Path)
+		switch change.Op {
+		case "add":
+			lines = append(lines, fmt.Sprintf("added %s = %s", field, describeValue(change.After)))
+		case "remove":
+			lines = append(lines, fmt.Sprintf("removed %s (was %s)", field, describeValue(change.Before)))
+		default:
+			lines = append(lines, fmt.Sprintf("changed %s from %s to %s", field, describeValue(change.Before), describeValue(change.After)))
+		}
+	}
+	return lines
+}
+
+// pointerToField converts a JSON pointer into the dotted field notation used by the rule editor
+func pointerToField(pointer string) string {
+	if pointer == "" {
+		return "(root)"
+	}
+	var field strings.Builder
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		if isIndex(token) {
+			field.WriteString("[" + token + "]")
+			continue
+		}
+		if field.Len() > 0 {
+			field.WriteString(".")
+		}
+		field.WriteString(token)
+	}
+	return field.String()
+}
+
+func isIndex(token string) bool {
+	if token == "" {
+		return false
+	}
+	for _, r := range token {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func describeValue(v interface{}) string {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(raw)
+}
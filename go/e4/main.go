@@ -4,6 +4,7 @@ import (
 	"context"
 	"log"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -20,6 +21,7 @@ import (
 	"github.com/prithvirajv06/nimbus-uta/go/engine/pkg/database"
 	"github.com/prithvirajv06/nimbus-uta/go/engine/pkg/messaging"
 	"github.com/prithvirajv06/nimbus-uta/go/engine/pkg/storage"
+	"google.golang.org/grpc"
 )
 
 func main() {
@@ -72,9 +74,21 @@ func main() {
 	router.Use(middleware.ErrorHandler())
 	apiV1 := router.Group("/api/v1")
 	// Customer Service and Handler
-	executionService := service.NewExecutionService(mongoDB, rabbitMQ, cfg)
+	executionService := service.NewExecutionService(mongoDB, redisClient, rabbitMQ, cfg)
 	executionHandler := handler.NewExecutionHandler(executionService)
 	executionHandler.RegisterRoutes(apiV1)
+	if err := executionService.StartExecutionConsumer(); err != nil {
+		log.Fatalf("Failed to start execution consumer: %v", err)
+	}
+	aliasService := service.NewVersionAliasService(mongoDB, cfg)
+	aliasHandler := handler.NewVersionAliasHandler(aliasService)
+	aliasHandler.RegisterRoutes(apiV1)
+	decisionTableService := service.NewDecisionTableService(mongoDB, redisClient, cfg)
+	decisionTableHandler := handler.NewDecisionTableHandler(decisionTableService)
+	decisionTableHandler.RegisterRoutes(apiV1)
+	logicFlowService := service.NewLogicFlowService(mongoDB, cfg)
+	logicFlowHandler := handler.NewLogicFlowHandler(logicFlowService)
+	logicFlowHandler.RegisterRoutes(apiV1)
 
 	// Create HTTP server
 	srv := &http.Server{
@@ -82,6 +96,23 @@ func main() {
 		Handler: router,
 	}
 
+	// gRPC server for typed engine invocation, sharing the execution service with REST
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(middleware.GRPCAuthUnary()),
+		grpc.StreamInterceptor(middleware.GRPCAuthStream()),
+	)
+	handler.NewExecutionGRPCHandler(executionService).RegisterServer(grpcServer)
+	grpcListener, err := net.Listen("tcp", ":"+cfg.Server.GRPCPort)
+	if err != nil {
+		log.Fatalf("Failed to listen for gRPC: %v", err)
+	}
+	go func() {
+		slog.Info("Starting gRPC server on port " + cfg.Server.GRPCPort)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			slog.Error("gRPC server failed: " + err.Error())
+		}
+	}()
+
 	// Start server in goroutine
 	go func() {
 		slog.Info("Starting server on port " + cfg.Server.Port)
@@ -102,6 +133,7 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		slog.Error("Server forced to shutdown: " + err.Error())
 	}
+	grpcServer.GracefulStop()
 
 	slog.Info("Server exited")
 }
@@ -0,0 +1,111 @@
+package dtable
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+)
+
+// TestAnalyze checks the findings of tables with overlapping, unreachable and duplicate rows, and
+// gaps, with and without validity periods. Messages are left out of the comparison.
+func TestAnalyze(t *testing.T) {
+	age := []models.Variables{column("data.age", "number")}
+	ageCountry := []models.Variables{column("data.age", "number"), column("data.country", "string")}
+	risk := []models.Variables{column("data.risk", "string")}
+	dated := func(table models.DecisionTable, periods ...[2]string) models.DecisionTable {
+		for i, period := range periods {
+			effective(t, &table, i, period[0], period[1])
+		}
+		return table
+	}
+	for _, c := range []struct {
+		name  string
+		table models.DecisionTable
+		want  []Finding
+	}{
+		{
+			name:  "complete table",
+			table: decisionTable("UNIQUE", age, risk, []string{"< 25", `"HIGH"`}, []string{">= 25", `"LOW"`}),
+			want:  []Finding{},
+		},
+		{
+			name: "overlap and gaps under UNIQUE",
+			table: decisionTable("UNIQUE", ageCountry, risk,
+				[]string{"< 25", "-", `"HIGH"`},
+				[]string{"[20..120]", `"IN","US"`, `"LOW"`}),
+			want: []Finding{
+				{Kind: FindingOverlap, Rows: []int{1, 2}, Inputs: map[string]string{"data.age": "[20..25)", "data.country": `"IN", "US"`}},
+				{Kind: FindingGap, Inputs: map[string]string{"data.age": "> 120", "data.country": "-"}},
+				{Kind: FindingGap, Inputs: map[string]string{"data.age": "[25..120]", "data.country": `not("IN", "US")`}},
+			},
+		},
+		{
+			name: "row shadowed under FIRST",
+			table: decisionTable("FIRST", ageCountry, risk,
+				[]string{"< 25", "-", `"HIGH"`},
+				[]string{"< 18", `"US"`, `"LOW"`},
+				[]string{">= 25", "-", `"LOW"`}),
+			want: []Finding{{Kind: FindingUnreachable, Rows: []int{1, 2}}},
+		},
+		{
+			name: "duplicate rows and a gap under ANY",
+			table: decisionTable("ANY", age, risk,
+				[]string{"< 25", `"HIGH"`},
+				[]string{"< 25", `"HIGH"`},
+				[]string{"[25..50]", `"LOW"`}),
+			want: []Finding{
+				{Kind: FindingDuplicate, Rows: []int{1, 2}},
+				{Kind: FindingGap, Inputs: map[string]string{"data.age": "> 50"}},
+			},
+		},
+		{
+			name: "rows effective one after the other do not overlap",
+			table: dated(decisionTable("UNIQUE", age, risk,
+				[]string{"< 25", `"HIGH"`},
+				[]string{"< 25", `"LOW"`},
+				[]string{">= 25", `"MID"`}), [2]string{"", "2025-01-01"}, [2]string{"2025-01-01", ""}),
+			want: []Finding{},
+		},
+		{
+			name: "rows effective at the same time conflict",
+			table: dated(decisionTable("UNIQUE", age, risk,
+				[]string{"< 25", `"HIGH"`},
+				[]string{"< 25", `"LOW"`},
+				[]string{">= 25", `"MID"`}), [2]string{"", "2025-01-01"}, [2]string{"2024-06-01", ""}),
+			want: []Finding{{Kind: FindingDuplicate, Rows: []int{1, 2}}},
+		},
+		{
+			name:  "gaps outside the only row's period",
+			table: dated(decisionTable("UNIQUE", age, risk, []string{"-", `"HIGH"`}), [2]string{"2024-01-01", "2025-01-01"}),
+			want: []Finding{
+				{Kind: FindingGap, Inputs: map[string]string{"data.age": "-"}, Period: "before 2024-01-01"},
+				{Kind: FindingGap, Inputs: map[string]string{"data.age": "-"}, Period: "from 2025-01-01"},
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			analysis, err := Analyze(c.table)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]Finding, len(analysis.Findings))
+			for i, finding := range analysis.Findings {
+				if finding.Message == "" {
+					t.Errorf("finding %d has no message", i)
+				}
+				finding.Message = ""
+				got[i] = finding
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("findings %+v, expected %+v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRejectsUnknownHitPolicy(t *testing.T) {
+	if _, err := Analyze(models.DecisionTable{HitPolicy: "SOMETIMES"}); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,101 @@
+package dtable
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestCoreCopies checks that core's copies of this package and of feel match the engine's files
+// of the same name. Comments, the module path and the JavaScript translation, which only the
+// engine has, are left out of the comparison.
+func TestCoreCopies(t *testing.T) {
+	for _, pkg := range []string{"dtable", "feel"} {
+		files, err := filepath.Glob(filepath.Join("..", "..", "..", "core", "pkg", pkg, "*.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(files) == 0 {
+			t.Skip("core is not checked out next to the engine")
+		}
+		for _, file := range files {
+			if strings.HasSuffix(file, "_test.go") {
+				continue
+			}
+			name := pkg + "/" + filepath.Base(file)
+			core := goSource(t, file, false)
+			engine := goSource(t, filepath.Join("..", pkg, filepath.Base(file)), true)
+			if core != engine {
+				t.Errorf("core's %s differs from the engine's:\ncore:   %s\nengine: %s", name, firstDifference(core, engine), firstDifference(engine, core))
+			}
+		}
+	}
+}
+
+// goSource reads a Go file as its code without comments or white space, with the engine's module
+// path in imports. With stripJS set, the js fields of structs and composite literals are removed.
+func goSource(t *testing.T, path string, stripJS bool) string {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, spec := range file.Imports {
+		spec.Path.Value = strings.Replace(spec.Path.Value, "nimbus-uta/go/core/", "nimbus-uta/go/engine/", 1)
+	}
+	if stripJS {
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch n := node.(type) {
+			case *ast.StructType:
+				fields := n.Fields.List[:0]
+				for _, field := range n.Fields.List {
+					if len(field.Names) != 1 || field.Names[0].Name != "js" {
+						fields = append(fields, field)
+					}
+				}
+				n.Fields.List = fields
+			case *ast.CompositeLit:
+				elts := n.Elts[:0]
+				for _, elt := range n.Elts {
+					if kv, ok := elt.(*ast.KeyValueExpr); !ok || !isIdent(kv.Key, "js") {
+						elts = append(elts, elt)
+					}
+				}
+				n.Elts = elts
+			}
+			return true
+		})
+	}
+	var out bytes.Buffer
+	if err := printer.Fprint(&out, fset, file); err != nil {
+		t.Fatal(err)
+	}
+	// Removed fields leave their lines behind, so the layout, down to trailing commas, is not compared
+	code := strings.Join(strings.Fields(out.String()), "")
+	return strings.NewReplacer(",}", "}", ",)", ")").Replace(code)
+}
+
+func isIdent(expr ast.Expr, name string) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == name
+}
+
+// firstDifference returns an excerpt of a from where it stops matching b
+func firstDifference(a, b string) string {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	end := i + 80
+	if end > len(a) {
+		end = len(a)
+	}
+	return strconv.Quote(a[i:end])
+}
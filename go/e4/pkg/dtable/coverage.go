@@ -0,0 +1,167 @@
+package dtable
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// Inputs kept as samples of documents no row matched and of failed evaluations
+const maxCoverageSamples = 100
+
+// CoverageReport tells how a dataset of fact documents exercises a table: how often each row
+// matched, which rows never did, which documents no row matched and how the outputs were spread.
+// Under FIRST a row only counts when no earlier row matched, as evaluation stops at the first match.
+type CoverageReport struct {
+	Documents       int                      `json:"documents"`
+	Matched         int                      `json:"matched"`
+	Unmatched       int                      `json:"unmatched"`
+	Failed          int                      `json:"failed"`
+	Rows            []RowCoverage            `json:"rows"`
+	NeverMatched    []int                    `json:"never_matched"`
+	UnmatchedInputs []CoverageSample         `json:"unmatched_inputs"`
+	Failures        []CoverageSample         `json:"failures"`
+	Outputs         map[string][]OutputCount `json:"outputs"`
+	Truncated       bool                     `json:"truncated,omitempty"` // Past maxCoverageSamples samples
+}
+
+// RowCoverage counts the documents a row matched
+type RowCoverage struct {
+	Row     int `json:"row"`
+	Matches int `json:"matches"`
+}
+
+// CoverageSample is a document of the dataset, by its 0-based position, with why it stands out
+type CoverageSample struct {
+	Index int                    `json:"index"`
+	Input map[string]interface{} `json:"input,omitempty"`
+	Error string                 `json:"error,omitempty"`
+}
+
+// OutputCount counts the documents for which an output column took a value. Each element of a
+// list output counts on its own.
+type OutputCount struct {
+	Value interface{} `json:"value"`
+	Count int         `json:"count"`
+}
+
+// Coverage evaluates the documents of a dataset one at a time and builds a CoverageReport
+type Coverage struct {
+	table   *Table
+	report  CoverageReport
+	matches map[int]int
+	outputs map[string]map[string]*OutputCount
+}
+
+// NewCoverage starts an empty report for the table
+func NewCoverage(t *Table) *Coverage {
+	return &Coverage{table: t, matches: map[int]int{}, outputs: map[string]map[string]*OutputCount{}}
+}
+
+// Add evaluates the document at index of the dataset with the rows effective at the given date.
+// It only fails when ctx is done; evaluation errors are counted as failures.
+func (cov *Coverage) Add(ctx context.Context, index int, facts map[string]interface{}, at time.Time) error {
+	var input map[string]interface{}
+	if len(cov.report.UnmatchedInputs) < maxCoverageSamples || len(cov.report.Failures) < maxCoverageSamples {
+		// Evaluation writes the outputs into facts
+		input, _ = normalize(facts).(map[string]interface{})
+	}
+	result, err := cov.table.EvaluateAt(ctx, facts, at, false)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if result != nil {
+		// Rows matched before a hit policy violation are not dead either
+		for _, number := range result.MatchedRows {
+			cov.matches[number]++
+		}
+	}
+	if err != nil {
+		cov.fail(CoverageSample{Index: index, Input: input, Error: err.Error()})
+		return nil
+	}
+	cov.report.Documents++
+	if len(result.MatchedRows) == 0 {
+		cov.report.Unmatched++
+		cov.sample(&cov.report.UnmatchedInputs, CoverageSample{Index: index, Input: input})
+	} else {
+		cov.report.Matched++
+	}
+	for key, value := range result.Outputs {
+		values, isList := value.([]interface{})
+		if !isList {
+			values = []interface{}{value}
+		}
+		for _, v := range values {
+			cov.countOutput(key, v)
+		}
+	}
+	return nil
+}
+
+// AddError counts a document of the dataset that could not be read or evaluated
+func (cov *Coverage) AddError(index int, err error) {
+	cov.fail(CoverageSample{Index: index, Error: err.Error()})
+}
+
+func (cov *Coverage) fail(sample CoverageSample) {
+	cov.report.Documents++
+	cov.report.Failed++
+	cov.sample(&cov.report.Failures, sample)
+}
+
+func (cov *Coverage) sample(samples *[]CoverageSample, sample CoverageSample) {
+	if len(*samples) >= maxCoverageSamples {
+		cov.report.Truncated = true
+		return
+	}
+	*samples = append(*samples, sample)
+}
+
+func (cov *Coverage) countOutput(key string, value interface{}) {
+	counts := cov.outputs[key]
+	if counts == nil {
+		counts = map[string]*OutputCount{}
+		cov.outputs[key] = counts
+	}
+	text := formatValue(value)
+	if counts[text] == nil {
+		counts[text] = &OutputCount{Value: value}
+	}
+	counts[text].Count++
+}
+
+// Report returns the report of the documents added so far. Rows are in rule order, and output
+// values from the most to the least frequent.
+func (cov *Coverage) Report() *CoverageReport {
+	report := cov.report
+	report.Rows = make([]RowCoverage, 0, len(cov.table.rows))
+	report.NeverMatched = []int{}
+	for _, r := range cov.table.rows {
+		report.Rows = append(report.Rows, RowCoverage{Row: r.number, Matches: cov.matches[r.number]})
+		if cov.matches[r.number] == 0 {
+			report.NeverMatched = append(report.NeverMatched, r.number)
+		}
+	}
+	if report.UnmatchedInputs == nil {
+		report.UnmatchedInputs = []CoverageSample{}
+	}
+	if report.Failures == nil {
+		report.Failures = []CoverageSample{}
+	}
+	report.Outputs = map[string][]OutputCount{}
+	for _, column := range cov.table.outputs {
+		counts := make([]OutputCount, 0, len(cov.outputs[column.VarKey]))
+		for _, count := range cov.outputs[column.VarKey] {
+			counts = append(counts, *count)
+		}
+		sort.Slice(counts, func(i, j int) bool {
+			if counts[i].Count != counts[j].Count {
+				return counts[i].Count > counts[j].Count
+			}
+			return formatValue(counts[i].Value) < formatValue(counts[j].Value)
+		})
+		report.Outputs[column.VarKey] = counts
+	}
+	return &report
+}
@@ -0,0 +1,74 @@
+package dtable
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+)
+
+func TestCoverage(t *testing.T) {
+	table := decisionTable("FIRST", []models.Variables{column("data.age", "number")}, []models.Variables{column("data.risk", "string")},
+		[]string{"< 25", `"HIGH"`},
+		[]string{"< 18", `"HIGHER"`},
+		[]string{"[25..65]", `"LOW"`})
+	compiled, err := Compile(table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coverage := NewCoverage(compiled)
+	for i, age := range []interface{}{20.0, 30.0, 40.0, 10.0, 70.0} {
+		if err := coverage.Add(context.Background(), i, map[string]interface{}{"age": age}, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	coverage.AddError(5, errors.New("invalid JSON"))
+	report := coverage.Report()
+
+	if report.Documents != 6 || report.Matched != 4 || report.Unmatched != 1 || report.Failed != 1 {
+		t.Errorf("counted %d documents, %d matched, %d unmatched and %d failed, expected 6, 4, 1 and 1",
+			report.Documents, report.Matched, report.Unmatched, report.Failed)
+	}
+	// Row 2 only matches documents row 1 already took under FIRST
+	if want := []RowCoverage{{Row: 1, Matches: 2}, {Row: 2, Matches: 0}, {Row: 3, Matches: 2}}; !reflect.DeepEqual(report.Rows, want) {
+		t.Errorf("rows %v, expected %v", report.Rows, want)
+	}
+	if !reflect.DeepEqual(report.NeverMatched, []int{2}) {
+		t.Errorf("never matched %v, expected [2]", report.NeverMatched)
+	}
+	if len(report.UnmatchedInputs) != 1 || report.UnmatchedInputs[0].Index != 4 || report.UnmatchedInputs[0].Input["age"] != 70.0 {
+		t.Errorf("unmatched inputs %v, expected document 4 with its input", report.UnmatchedInputs)
+	}
+	if len(report.Failures) != 1 || report.Failures[0].Index != 5 {
+		t.Errorf("failures %v, expected document 5", report.Failures)
+	}
+	if want := []OutputCount{{Value: "HIGH", Count: 2}, {Value: "LOW", Count: 2}}; !reflect.DeepEqual(report.Outputs["data.risk"], want) {
+		t.Errorf("outputs %v, expected %v", report.Outputs["data.risk"], want)
+	}
+}
+
+// TestCoverageCountsHitPolicyViolations checks that a document breaking UNIQUE fails, while the
+// rows it matched still count as reached
+func TestCoverageCountsHitPolicyViolations(t *testing.T) {
+	table := decisionTable("UNIQUE", []models.Variables{column("data.age", "number")}, []models.Variables{column("data.risk", "string")},
+		[]string{"< 25", `"HIGH"`},
+		[]string{"< 30", `"LOW"`})
+	compiled, err := Compile(table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coverage := NewCoverage(compiled)
+	if err := coverage.Add(context.Background(), 0, map[string]interface{}{"age": 20.0}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	report := coverage.Report()
+	if report.Failed != 1 || len(report.Failures) != 1 || report.Failures[0].Error == "" {
+		t.Errorf("failures %v, expected the hit policy violation", report.Failures)
+	}
+	if len(report.NeverMatched) != 0 {
+		t.Errorf("never matched %v, expected both rows reached", report.NeverMatched)
+	}
+}
@@ -0,0 +1,83 @@
+package dtable
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+)
+
+// CycleError is a chain of requirements leading back to where it started. Path names each table
+// version on the way, ending with the first one again.
+type CycleError struct {
+	Path []string `json:"path"`
+}
+
+func (e *CycleError) Error() string {
+	return "decision tables form a cycle: " + strings.Join(e.Path, " -> ")
+}
+
+// Resolve walks the decision requirements graph of root and returns its tables in dependency
+// order, every table after the tables it requires and root last. load fetches a required table
+// version; root itself is never loaded, so a table can be checked before it is saved.
+func Resolve(root *models.DecisionTable, load func(nimbID string, version int) (*models.DecisionTable, error)) ([]*models.DecisionTable, error) {
+	r := &resolver{load: load, state: map[string]int{}, tables: map[string]*models.DecisionTable{}}
+	r.tables[tableKey(root.NIMB_ID, root.Audit.Version)] = root
+	if err := r.visit(root.NIMB_ID, root.Audit.Version); err != nil {
+		return nil, err
+	}
+	return r.order, nil
+}
+
+// Visit states of a table version while walking the graph
+const (
+	unvisited = iota
+	visiting
+	visited
+)
+
+type resolver struct {
+	load   func(nimbID string, version int) (*models.DecisionTable, error)
+	state  map[string]int
+	tables map[string]*models.DecisionTable
+	path   []string
+	order  []*models.DecisionTable
+}
+
+func (r *resolver) visit(nimbID string, version int) error {
+	key := tableKey(nimbID, version)
+	switch r.state[key] {
+	case visited:
+		return nil
+	case visiting:
+		for i, step := range r.path {
+			if step == key {
+				return &CycleError{Path: append(append([]string{}, r.path[i:]...), key)}
+			}
+		}
+	}
+	table, ok := r.tables[key]
+	if !ok {
+		var err error
+		if table, err = r.load(nimbID, version); err != nil {
+			return fmt.Errorf("required decision table %s: %w", key, err)
+		}
+		r.tables[key] = table
+	}
+	r.state[key] = visiting
+	r.path = append(r.path, key)
+	for _, required := range table.Requires {
+		if err := r.visit(required.NIMB_ID, required.Version); err != nil {
+			return err
+		}
+	}
+	r.path = r.path[:len(r.path)-1]
+	r.state[key] = visited
+	r.order = append(r.order, table)
+	return nil
+}
+
+func tableKey(nimbID string, version int) string {
+	return nimbID + " v" + strconv.Itoa(version)
+}
@@ -0,0 +1,96 @@
+package dtable
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+)
+
+// requiring builds version 1 of a table that requires version 1 of the others
+func requiring(nimbID string, required ...string) *models.DecisionTable {
+	table := &models.DecisionTable{NIMB_ID: nimbID}
+	table.Audit.Version = 1
+	for _, r := range required {
+		table.Requires = append(table.Requires, models.DecisionRequirement{NIMB_ID: r, Version: 1})
+	}
+	return table
+}
+
+func TestResolve(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		root   *models.DecisionTable
+		stored []*models.DecisionTable
+		want   []string
+		cycle  []string
+		err    string
+	}{
+		{name: "no requirements", root: requiring("A"), want: []string{"A"}},
+		{
+			name:   "chain",
+			root:   requiring("A", "B"),
+			stored: []*models.DecisionTable{requiring("B", "C"), requiring("C")},
+			want:   []string{"C", "B", "A"},
+		},
+		{
+			name:   "shared requirement is evaluated once",
+			root:   requiring("A", "B", "C"),
+			stored: []*models.DecisionTable{requiring("B", "D"), requiring("C", "D"), requiring("D")},
+			want:   []string{"D", "B", "C", "A"},
+		},
+		{
+			name:   "cycle through the root",
+			root:   requiring("A", "B"),
+			stored: []*models.DecisionTable{requiring("B", "C"), requiring("C", "A")},
+			cycle:  []string{"A v1", "B v1", "C v1", "A v1"},
+		},
+		{
+			name:   "cycle below the root",
+			root:   requiring("A", "B"),
+			stored: []*models.DecisionTable{requiring("B", "C"), requiring("C", "B")},
+			cycle:  []string{"B v1", "C v1", "B v1"},
+		},
+		{
+			name: "missing requirement",
+			root: requiring("A", "B"),
+			err:  "required decision table B v1: not found",
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			stored := map[string]*models.DecisionTable{}
+			for _, table := range c.stored {
+				stored[table.NIMB_ID] = table
+			}
+			order, err := Resolve(c.root, func(nimbID string, version int) (*models.DecisionTable, error) {
+				if table, ok := stored[nimbID]; ok && version == 1 {
+					return table, nil
+				}
+				return nil, errors.New("not found")
+			})
+			var cycle *CycleError
+			switch {
+			case c.cycle != nil:
+				if !errors.As(err, &cycle) || !reflect.DeepEqual(cycle.Path, c.cycle) {
+					t.Errorf("Resolve = %v, expected the cycle %s", err, strings.Join(c.cycle, " -> "))
+				}
+			case c.err != "":
+				if err == nil || err.Error() != c.err {
+					t.Errorf("Resolve = %v, expected %q", err, c.err)
+				}
+			case err != nil:
+				t.Fatal(err)
+			default:
+				got := make([]string, len(order))
+				for i, table := range order {
+					got[i] = table.NIMB_ID
+				}
+				if !reflect.DeepEqual(got, c.want) {
+					t.Errorf("order %v, expected %v", got, c.want)
+				}
+			}
+		})
+	}
+}
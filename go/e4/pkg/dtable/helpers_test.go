@@ -0,0 +1,54 @@
+package dtable
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+)
+
+// column declares an input or output column of a test table
+func column(varKey, varType string) models.Variables {
+	return models.Variables{VarKey: varKey, Type: varType}
+}
+
+// decisionTable lays out rows of entries, the input entries first, under the hit policy
+func decisionTable(policy string, inputs, outputs []models.Variables, rows ...[]string) models.DecisionTable {
+	table := models.DecisionTable{Name: "test", HitPolicy: policy, InputsColumns: inputs, OutputsColumns: outputs}
+	for _, entries := range rows {
+		cells := make([]models.Variables, len(entries))
+		for c, entry := range entries {
+			if c < len(inputs) {
+				cells[c] = inputs[c]
+			} else {
+				cells[c] = outputs[c-len(inputs)]
+			}
+			cells[c].Value = entry
+		}
+		table.Rules = append(table.Rules, cells)
+	}
+	return table
+}
+
+// effective sets the validity period of row i, 0-based; an empty bound stays open. The table then
+// has metadata for every row.
+func effective(t testing.TB, table *models.DecisionTable, i int, from, to string) {
+	t.Helper()
+	for len(table.RulesMeta) < len(table.Rules) {
+		table.RulesMeta = append(table.RulesMeta, models.RuleMeta{})
+	}
+	table.RulesMeta[i].ValidFrom = date(t, from)
+	table.RulesMeta[i].ValidTo = date(t, to)
+}
+
+func date(t testing.TB, text string) *time.Time {
+	t.Helper()
+	if text == "" {
+		return nil
+	}
+	d, err := ParseDate(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &d
+}
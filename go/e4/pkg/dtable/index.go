@@ -0,0 +1,238 @@
+package dtable
+
+import (
+	"math/bits"
+	"sort"
+	"strings"
+
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/pkg/feel"
+)
+
+// Tables with fewer rows are scanned, as building and probing indexes would not pay off
+const minIndexedRows = 32
+
+// index narrows the rows a fact document can match before they are checked one by one. Each
+// indexed input column maps the values its entries name exactly to rows, through an equality map
+// for lists of values and an interval tree for numeric ranges; rows whose entry fits neither, such
+// as "-" or not("X"), are candidates for every value.
+type index struct {
+	columns []columnIndex
+	words   int
+}
+
+type columnIndex struct {
+	column   int
+	wildcard bitset
+	points   map[indexKey]bitset
+	ranges   *intervalTree
+}
+
+// indexKey tells numbers, strings and booleans apart, since a number entry never matches a string fact
+type indexKey struct {
+	kind  byte
+	value interface{}
+}
+
+type bitset []uint64
+
+// buildIndex indexes the FEEL input columns whose entries are mostly lists of values or numeric
+// ranges. It returns nil when no column is worth indexing.
+func buildIndex(language string, inputs []models.Variables, rows []row) *index {
+	if len(rows) < minIndexedRows || strings.EqualFold(language, LanguageNimbus) {
+		return nil
+	}
+	idx := &index{words: (len(rows) + 63) / 64}
+	for c, column := range inputs {
+		if strings.Contains(column.VarKey, "[*]") {
+			continue
+		}
+		col := columnIndex{column: c, wildcard: make(bitset, idx.words), points: map[indexKey]bitset{}}
+		var intervals []treeInterval
+		wildcards := 0
+		for i, r := range rows {
+			points, ranges, ok := entryValues(r.tests[c])
+			switch {
+			case !ok:
+				col.wildcard.set(i)
+				wildcards++
+			case ranges != nil:
+				for _, between := range ranges {
+					intervals = append(intervals, treeInterval{Range: between, row: i})
+				}
+			default:
+				for _, point := range points {
+					key, _ := keyOf(point)
+					if col.points[key] == nil {
+						col.points[key] = make(bitset, idx.words)
+					}
+					col.points[key].set(i)
+				}
+			}
+		}
+		// A column of mostly wildcards hardly narrows anything
+		if wildcards*2 > len(rows) {
+			continue
+		}
+		if len(intervals) > 0 {
+			col.ranges = newIntervalTree(intervals)
+		}
+		idx.columns = append(idx.columns, col)
+	}
+	if len(idx.columns) == 0 {
+		return nil
+	}
+	return idx
+}
+
+// entryValues reads the values an input entry matches exactly: points for lists of values, ranges
+// for numeric intervals. ok is false for entries the index cannot describe.
+func entryValues(test cellTest) (points []interface{}, ranges []feel.Range, ok bool) {
+	if test.unary == nil {
+		return nil, nil, false
+	}
+	if points, ok := test.unary.Points(); ok {
+		return points, nil, true
+	}
+	if ranges, ok := test.unary.NumberRanges(); ok {
+		return nil, ranges, true
+	}
+	return nil, nil, false
+}
+
+// keyOf returns the index key of a fact or literal value; ok is false for values no point can equal
+func keyOf(value interface{}) (indexKey, bool) {
+	switch v := value.(type) {
+	case string:
+		return indexKey{kind: 's', value: v}, true
+	case bool:
+		return indexKey{kind: 'b', value: v}, true
+	}
+	if n, ok := number(value); ok {
+		return indexKey{kind: 'n', value: n}, true
+	}
+	return indexKey{}, false
+}
+
+// number converts the numeric fact types FEEL compares with numbers. Unlike toFloat it does not
+// read numeric strings.
+func number(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// candidates returns the rows that may match the input values, in rule order
+func (idx *index) candidates(values []interface{}) []int {
+	var result bitset
+	for _, col := range idx.columns {
+		matching := append(make(bitset, 0, idx.words), col.wildcard...)
+		if key, ok := keyOf(values[col.column]); ok {
+			matching.or(col.points[key])
+		}
+		if n, ok := number(values[col.column]); ok && col.ranges != nil {
+			col.ranges.stab(n, func(row int) { matching.set(row) })
+		}
+		if result == nil {
+			result = matching
+		} else {
+			result.and(matching)
+		}
+	}
+	return result.members()
+}
+
+func (b bitset) set(i int) {
+	b[i/64] |= 1 << (i % 64)
+}
+
+func (b bitset) or(o bitset) {
+	for i := range o {
+		b[i] |= o[i]
+	}
+}
+
+func (b bitset) and(o bitset) {
+	for i := range b {
+		b[i] &= o[i]
+	}
+}
+
+func (b bitset) members() []int {
+	var out []int
+	for i, word := range b {
+		for word != 0 {
+			out = append(out, i*64+bits.TrailingZeros64(word))
+			word &= word - 1
+		}
+	}
+	return out
+}
+
+// treeInterval is a numeric range of a row's entry
+type treeInterval struct {
+	feel.Range
+	row int
+}
+
+// intervalTree is a static interval tree: the intervals sorted by lower bound form an implicit
+// balanced tree, the middle of each slice being its root, and maxHi holds the largest upper bound
+// of each subtree so that stabbing queries skip subtrees ending below the value
+type intervalTree struct {
+	intervals []treeInterval
+	maxHi     []float64
+}
+
+func newIntervalTree(intervals []treeInterval) *intervalTree {
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].Lo < intervals[j].Lo })
+	t := &intervalTree{intervals: intervals, maxHi: make([]float64, len(intervals))}
+	t.build(0, len(intervals))
+	return t
+}
+
+func (t *intervalTree) build(lo, hi int) float64 {
+	mid := (lo + hi) / 2
+	maxHi := t.intervals[mid].Hi
+	if lo < mid {
+		maxHi = max(maxHi, t.build(lo, mid))
+	}
+	if mid+1 < hi {
+		maxHi = max(maxHi, t.build(mid+1, hi))
+	}
+	t.maxHi[mid] = maxHi
+	return maxHi
+}
+
+// stab calls visit with the row of every interval containing n
+func (t *intervalTree) stab(n float64, visit func(row int)) {
+	t.search(0, len(t.intervals), n, visit)
+}
+
+func (t *intervalTree) search(lo, hi int, n float64, visit func(row int)) {
+	if lo >= hi {
+		return
+	}
+	mid := (lo + hi) / 2
+	if t.maxHi[mid] < n {
+		return
+	}
+	t.search(lo, mid, n, visit)
+	if t.intervals[mid].Lo > n {
+		// Intervals after mid start later still
+		return
+	}
+	if t.intervals[mid].Contains(n) {
+		visit(t.intervals[mid].row)
+	}
+	t.search(mid+1, hi, n, visit)
+}
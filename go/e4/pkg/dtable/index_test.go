@@ -0,0 +1,122 @@
+package dtable
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+)
+
+// Rows of the generated pricing table the index is checked and benchmarked on
+const benchmarkRows = 10000
+
+// TestIndexMatchesScan checks that evaluating the pricing table through its index matches the
+// same rows as scanning every row, for a sample of fact documents
+func TestIndexMatchesScan(t *testing.T) {
+	indexed, scanned := pricingTables(t)
+	for i, fact := range sampleFacts() {
+		want, wantErr := scanned.Evaluate(context.Background(), copyFacts(fact), false)
+		got, gotErr := indexed.Evaluate(context.Background(), copyFacts(fact), false)
+		if fmt.Sprint(wantErr) != fmt.Sprint(gotErr) || (wantErr == nil && !reflect.DeepEqual(want.MatchedRows, got.MatchedRows)) {
+			t.Errorf("fact %d: scanning matched %v (%v), the index matched %v (%v)", i, want.MatchedRows, wantErr, got.MatchedRows, gotErr)
+		}
+	}
+}
+
+// BenchmarkIndex evaluates the pricing table with and without its index
+func BenchmarkIndex(b *testing.B) {
+	indexed, scanned := pricingTables(b)
+	facts := sampleFacts()
+	for _, bench := range []struct {
+		name  string
+		table *Table
+	}{{"scan", scanned}, {"index", indexed}} {
+		b.Run(bench.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				bench.table.Evaluate(context.Background(), copyFacts(facts[i%len(facts)]), false)
+			}
+		})
+	}
+}
+
+// pricingTables compiles the pricing table, returning it indexed and a copy that scans every row
+func pricingTables(tb testing.TB) (*Table, *Table) {
+	tb.Helper()
+	indexed, err := Compile(pricingTable(benchmarkRows))
+	if err != nil {
+		tb.Fatalf("compile failed: %v", err)
+	}
+	if indexed.index == nil {
+		tb.Fatal("table was not indexed")
+	}
+	scanned := *indexed
+	scanned.index = nil
+	return indexed, &scanned
+}
+
+// sampleFacts draws fact documents across the table's regions, products, quantities and channels,
+// including products no row names
+func sampleFacts() []map[string]interface{} {
+	random := rand.New(rand.NewSource(1))
+	facts := make([]map[string]interface{}, 1000)
+	for i := range facts {
+		facts[i] = map[string]interface{}{
+			"region":   "R" + strconv.Itoa(random.Intn(25)),
+			"product":  "P" + strconv.Itoa(random.Intn(45)),
+			"quantity": float64(random.Intn(1100)),
+			"channel":  []string{"web", "store", "partner"}[random.Intn(3)],
+		}
+	}
+	return facts
+}
+
+// pricingTable lays out prices by region, product and quantity band, with a channel surcharge on
+// every tenth row, under FIRST
+func pricingTable(rows int) models.DecisionTable {
+	inputs := []models.Variables{
+		{VarKey: "data.region", Type: "string"},
+		{VarKey: "data.product", Type: "string"},
+		{VarKey: "data.quantity", Type: "number"},
+		{VarKey: "data.channel", Type: "string"},
+	}
+	outputs := []models.Variables{{VarKey: "data.price", Type: "number"}}
+	table := models.DecisionTable{Name: "pricing", HitPolicy: First, InputsColumns: inputs, OutputsColumns: outputs}
+	for i := 0; i < rows; i++ {
+		band := i % 10
+		channel := "-"
+		if i%10 == 9 {
+			channel = `"partner"`
+		}
+		entries := []string{
+			strconv.Quote("R" + strconv.Itoa(i/400%25)),
+			strconv.Quote("P" + strconv.Itoa(i/10%40)),
+			fmt.Sprintf("[%d..%d)", band*100, band*100+100),
+			channel,
+			strconv.Itoa(100 + i%97),
+		}
+		cells := make([]models.Variables, len(entries))
+		for c, entry := range entries {
+			if c < len(inputs) {
+				cells[c] = inputs[c]
+			} else {
+				cells[c] = outputs[c-len(inputs)]
+			}
+			cells[c].Value = entry
+		}
+		table.Rules = append(table.Rules, cells)
+	}
+	return table
+}
+
+func copyFacts(facts map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(facts))
+	for k, v := range facts {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,299 @@
+package dtable
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/pkg/feel"
+)
+
+// Names the generated script defines. Condition steps strip "<target>." from their statements, so
+// the table's conditions all target pipelineScope, which nothing in the script refers to.
+const (
+	pipelineScope = "__dt"
+	matchedVar    = "__dt_matched"
+	lookupHelper  = "__dt_get"
+	nowVar        = "__dt_now"
+)
+
+// lookupHelperSource reads a path of names below a document the way walk does
+const lookupHelperSource = `function (d, names) { for (var i = 0; i < names.length; i++) { ` +
+	`if (d == null || typeof d !== "object" || Array.isArray(d) || !Object.prototype.hasOwnProperty.call(d, names[i])) { return undefined; } ` +
+	`d = d[names[i]]; } return d; }`
+
+// ToPipeline compiles a FEEL table into engine pipeline steps, so that tables run on the engine
+// runtime with its script caching, limits and tracing. The steps compute the same outputs as
+// Evaluate: every matching row is recorded in rule order (in priority order under PRIORITY, which
+// then keeps the first match), the hit policy is checked, and the outputs are written into the
+// facts. Rows with a validity period are checked against the time the script runs. Pipeline
+// values must stay JSON, so helpers, policy violations and aggregates are expressions run by
+// conditions that are always false.
+func ToPipeline(table models.DecisionTable) ([]models.PipelineStep, error) {
+	if strings.EqualFold(table.ExpressionLanguage, LanguageNimbus) {
+		return nil, fmt.Errorf("only FEEL tables compile to pipelines, the table uses %s", LanguageNimbus)
+	}
+	if len(table.Requires) > 0 {
+		return nil, fmt.Errorf("the table requires other decision tables, which only graph evaluation resolves")
+	}
+	t, err := Compile(table)
+	if err != nil {
+		return nil, err
+	}
+	ranked := append([]row(nil), t.rows...)
+	sort.SliceStable(ranked, func(i, j int) bool { return t.higherPriority(ranked[i], ranked[j]) })
+
+	evaluated := t.rows
+	if t.Policy.Name == Priority {
+		evaluated = ranked
+	}
+	steps := []models.PipelineStep{
+		expressionStep(lookupHelper + " = " + lookupHelperSource),
+		assignStep(matchedVar, "[]"),
+	}
+	for _, r := range t.rows {
+		if r.validity.dated() {
+			steps = append(steps, expressionStep(nowVar+" = Date.now()"))
+			break
+		}
+	}
+	for _, r := range evaluated {
+		statement := t.rowJS(r)
+		if r.validity.dated() {
+			statement = validityJS(r.validity) + " && " + statement
+		}
+		if t.Policy.Name == First || t.Policy.Name == Priority {
+			statement = matchedVar + ".length === 0 && " + statement
+		}
+		steps = append(steps, conditionStep(statement, models.PipelineStep{
+			Type: "push_array", Target: matchedVar, Value: json.RawMessage(feel.JSString(strconv.Itoa(r.number))),
+		}))
+	}
+	steps = append(steps, t.policyChecks()...)
+	steps = append(steps, t.outputSteps(ranked))
+	for _, step := range steps {
+		if strings.Contains(step.Statement, feel.JSTimeHelper+"(") {
+			steps = append([]models.PipelineStep{expressionStep(feel.JSTimeHelper + " = " + feel.JSTimeHelperSource)}, steps...)
+			break
+		}
+	}
+	return []models.PipelineStep{conditionStep("true", steps...)}, nil
+}
+
+// rowJS writes the condition under which every input entry of the row holds
+func (t *Table) rowJS(r row) string {
+	var params, args, tests []string
+	for c, test := range r.tests {
+		if test.expr == anyValue.expr || test.js == nil {
+			continue
+		}
+		value := "v" + strconv.Itoa(c)
+		params = append(params, value)
+		args = append(args, lookupJS("data", strings.TrimPrefix(t.inputs[c].VarKey, factsRoot+".")))
+		if strings.Contains(t.inputs[c].VarKey, "[*]") {
+			// Array inputs hold when every element satisfies the entry
+			tests = append(tests, fmt.Sprintf("(Array.isArray(%s) && %s.every(function (e) { return %s; }))", value, value, test.js("e")))
+			continue
+		}
+		tests = append(tests, test.js(value))
+	}
+	if len(tests) == 0 {
+		return "true"
+	}
+	return fmt.Sprintf("(function (%s) { return %s; })(%s)", strings.Join(params, ", "), strings.Join(tests, " && "), strings.Join(args, ", "))
+}
+
+// validityJS writes the condition under which the row is effective when the script runs
+func validityJS(v validity) string {
+	var bounds []string
+	if !v.from.IsZero() {
+		bounds = append(bounds, fmt.Sprintf("%s >= %d", nowVar, v.from.UnixMilli()))
+	}
+	if !v.to.IsZero() {
+		bounds = append(bounds, fmt.Sprintf("%s < %d", nowVar, v.to.UnixMilli()))
+	}
+	return strings.Join(bounds, " && ")
+}
+
+// lookupJS writes an expression reading a variable path below base the way lookup does: undefined
+// where the path is missing and, past a [*], the array of the rest of the path of every element
+func lookupJS(base, path string) string {
+	head, rest, isArray := strings.Cut(path, "[*]")
+	expr := base
+	if head != "" {
+		expr = lookupHelper + "(" + base + ", " + jsJSON(strings.Split(head, ".")) + ")"
+	}
+	if !isArray {
+		return expr
+	}
+	elem := "e"
+	if rest = strings.TrimPrefix(rest, "."); rest != "" {
+		elem = lookupJS("e", rest)
+	}
+	return fmt.Sprintf("(function (a) { return Array.isArray(a) ? a.map(function (e) { return %s; }) : undefined; })(%s)", elem, expr)
+}
+
+// policyChecks fail the script the way applyHitPolicy fails when UNIQUE or ANY is violated
+func (t *Table) policyChecks() []models.PipelineStep {
+	switch t.Policy.Name {
+	case Unique:
+		return []models.PipelineStep{expressionStep(fmt.Sprintf(
+			`%s.length > 1 && %s`, matchedVar, throwJS(`"hit policy UNIQUE violated: rows " + `+matchedVar+`.join(", ") + " all match"`)))}
+	case Any:
+		// Rows with equal outputs share a group
+		groups := map[string]int{}
+		for i, r := range t.rows {
+			groups[strconv.Itoa(r.number)] = i
+			for _, earlier := range t.rows[:i] {
+				if reflect.DeepEqual(earlier.outputs, r.outputs) {
+					groups[strconv.Itoa(r.number)] = groups[strconv.Itoa(earlier.number)]
+					break
+				}
+			}
+		}
+		return []models.PipelineStep{expressionStep(fmt.Sprintf(
+			`(function (groups) { for (var i = 1; i < %[1]s.length; i++) { if (groups[%[1]s[i]] !== groups[%[1]s[0]]) { %[2]s; } } })(%[3]s)`,
+			matchedVar, `throw new Error("hit policy ANY violated: rows " + `+matchedVar+`[0] + " and " + `+matchedVar+`[i] + " match with different outputs")`, jsJSON(groups)))}
+	}
+	return nil
+}
+
+// outputSteps write the outputs of the matched rows once any row matched, or always under COLLECT
+// COUNT, which counts no matches as 0
+func (t *Table) outputSteps(ranked []row) models.PipelineStep {
+	var steps []models.PipelineStep
+	created := map[string]bool{}
+	for _, column := range t.outputs {
+		names := outputPath(column.VarKey)
+		for i := 1; i < len(names); i++ {
+			parent := targetJS(names[:i])
+			if created[parent] {
+				continue
+			}
+			created[parent] = true
+			steps = append(steps, conditionStep(
+				fmt.Sprintf(`%[1]s == null || typeof %[1]s !== "object" || Array.isArray(%[1]s)`, parent),
+				assignStep(parent, "{}")))
+		}
+	}
+
+	guard := matchedVar + ".length > 0"
+	switch {
+	case t.Policy.Name == Unique || t.Policy.Name == First || t.Policy.Name == Any || t.Policy.Name == Priority:
+		for _, r := range t.rows {
+			var assignments []models.PipelineStep
+			for o, column := range t.outputs {
+				assignments = append(assignments, assignStep(targetJS(outputPath(column.VarKey)), jsJSON(r.outputs[o])))
+			}
+			steps = append(steps, conditionStep(fmt.Sprintf("%s[0] === %d", matchedVar, r.number), assignments...))
+		}
+	case t.Policy.Aggregation == "":
+		order := t.rows
+		if t.Policy.Name == OutputOrder {
+			order = ranked
+		}
+		var numbers []int
+		for _, r := range order {
+			numbers = append(numbers, r.number)
+		}
+		for o, column := range t.outputs {
+			values := map[string]interface{}{}
+			for _, r := range order {
+				if r.outputs[o] != nil {
+					values[strconv.Itoa(r.number)] = r.outputs[o]
+				}
+			}
+			steps = append(steps, expressionStep(fmt.Sprintf(
+				`%s = (function (values) { return %s.filter(function (r) { return %s.indexOf(r) !== -1 && r in values; }).map(function (r) { return values[r]; }); })(%s)`,
+				targetJS(outputPath(column.VarKey)), jsJSON(numbers), matchedVar, jsJSON(values))))
+		}
+	default:
+		if t.Policy.Aggregation == "COUNT" {
+			guard = "true"
+		}
+		for o := range t.outputs {
+			steps = append(steps, t.aggregateStep(o))
+		}
+	}
+	return conditionStep(guard, steps...)
+}
+
+// aggregateStep writes the SUM, MIN, MAX or COUNT of an output column over the matched rows. The
+// values of the rows are known up front, so the script only folds them; like aggregate, it fails on
+// the first non-numeric value.
+func (t *Table) aggregateStep(o int) models.PipelineStep {
+	column := t.outputs[o]
+	target := targetJS(outputPath(column.VarKey))
+	numbers, invalid := map[string]float64{}, map[string]string{}
+	var counted []int
+	for _, r := range t.rows {
+		value := r.outputs[o]
+		if value == nil {
+			continue
+		}
+		counted = append(counted, r.number)
+		if f, ok := toFloat(value); ok {
+			numbers[strconv.Itoa(r.number)] = f
+			continue
+		}
+		invalid[strconv.Itoa(r.number)] = fmt.Sprintf("output %s: cannot aggregate non-numeric value %q with %s", column.VarKey, formatValue(value), t.Policy.Aggregation)
+	}
+	if t.Policy.Aggregation == "COUNT" {
+		return expressionStep(fmt.Sprintf(`%s = %s.filter(function (r) { return %s.indexOf(r) !== -1; }).length`, target, matchedVar, jsJSON(counted)))
+	}
+	start, fold := "0", "acc + numbers[r]"
+	switch t.Policy.Aggregation {
+	case "MIN":
+		start, fold = "Infinity", "Math.min(acc, numbers[r])"
+	case "MAX":
+		start, fold = "-Infinity", "Math.max(acc, numbers[r])"
+	}
+	return expressionStep(fmt.Sprintf(
+		`(function (numbers, invalid) { var found = false, acc = %s; `+
+			`for (var i = 0; i < %s.length; i++) { var r = %s[i]; if (invalid[r]) { throw new Error(invalid[r]); } if (r in numbers) { found = true; acc = %s; } } `+
+			`if (found) { %s = acc; } })(%s, %s)`,
+		start, matchedVar, matchedVar, fold, target, jsJSON(numbers), jsJSON(invalid)))
+}
+
+func conditionStep(statement string, children ...models.PipelineStep) models.PipelineStep {
+	return models.PipelineStep{Type: "condition", Target: pipelineScope, Statement: statement, Children: children}
+}
+
+func assignStep(target, value string) models.PipelineStep {
+	return models.PipelineStep{Type: "assignment", Target: target, Value: json.RawMessage(value)}
+}
+
+// expressionStep runs an expression for its effect, as the condition of a branch never taken
+func expressionStep(expr string) models.PipelineStep {
+	return conditionStep("(" + expr + ", false)")
+}
+
+func throwJS(message string) string {
+	return "(function () { throw new Error(" + message + "); })()"
+}
+
+func outputPath(varKey string) []string {
+	return strings.Split(strings.TrimPrefix(varKey, factsRoot+"."), ".")
+}
+
+// targetJS writes the facts member at a path, such as data["applicant"]["risk"]
+func targetJS(names []string) string {
+	target := "data"
+	for _, name := range names {
+		target += "[" + feel.JSString(name) + "]"
+	}
+	return target
+}
+
+// jsJSON encodes a value as JSON that can also be embedded in the script's single-quoted log messages
+func jsJSON(value interface{}) string {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return "null"
+	}
+	return strings.ReplaceAll(string(raw), "'", `\u0027`)
+}
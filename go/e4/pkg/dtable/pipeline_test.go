@@ -0,0 +1,172 @@
+package dtable
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/prithvirajv06/nimbus-uta/go/engine/engine"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+)
+
+// TestPipeline runs the scripts of compiled tables on fact documents, which must leave the facts
+// Evaluate leaves, or fail where Evaluate fails, under every hit policy and aggregation. failing
+// counts the documents on which both fail.
+func TestPipeline(t *testing.T) {
+	age := []models.Variables{column("data.age", "number")}
+	risk := []models.Variables{column("data.risk", "string")}
+	score := []models.Variables{column("data.score", "number")}
+	ranked := []models.Variables{{VarKey: "data.risk", Type: "string", Value: `"HIGH","MEDIUM","LOW"`}}
+	// Three rows over age, overlapping from 20 to 30 and from 35 to 40, with the given outputs
+	overlapping := func(policy string, outputs []models.Variables, entries ...[]string) models.DecisionTable {
+		return decisionTable(policy, age, outputs,
+			append([]string{"< 30"}, entries[0]...),
+			append([]string{"[20..40]"}, entries[1]...),
+			append([]string{"> 35"}, entries[2]...))
+	}
+	dated := decisionTable("FIRST", age, risk,
+		[]string{"-", `"EXPIRED"`},
+		[]string{"-", `"PENDING"`},
+		[]string{"< 30", `"YOUNG"`},
+		[]string{"-", `"OTHER"`})
+	effective(t, &dated, 0, "2000-01-01", "2001-01-01")
+	effective(t, &dated, 1, "2999-01-01", "")
+	effective(t, &dated, 2, "2000-01-01", "")
+	ages := []string{`{"age": 10}`, `{"age": 25}`, `{"age": 38}`, `{"age": 50}`, `{}`, `{"age": "x"}`}
+
+	for _, c := range []struct {
+		name    string
+		table   models.DecisionTable
+		facts   []string
+		failing int
+	}{
+		{
+			name:    "UNIQUE, violated where rows overlap",
+			table:   overlapping("UNIQUE", risk, []string{`"LOW"`}, []string{`"MEDIUM"`}, []string{`"HIGH"`}),
+			facts:   ages,
+			failing: 2,
+		},
+		{
+			name:    "ANY, violated where overlapping rows differ",
+			table:   overlapping("ANY", risk, []string{`"LOW"`}, []string{`"LOW"`}, []string{`"HIGH"`}),
+			facts:   ages,
+			failing: 1,
+		},
+		{
+			name:  "FIRST",
+			table: overlapping("FIRST", risk, []string{`"LOW"`}, []string{`"MEDIUM"`}, []string{`"HIGH"`}),
+			facts: ages,
+		},
+		{
+			name:  "PRIORITY ranks by allowed values",
+			table: overlapping("PRIORITY", ranked, []string{`"LOW"`}, []string{`"HIGH"`}, []string{`"MEDIUM"`}),
+			facts: ages,
+		},
+		{
+			name:  "PRIORITY ranks by __priority__",
+			table: overlapping("P", []models.Variables{column("data.risk", "string"), column("__priority__", "number")}, []string{`"LOW"`, "1"}, []string{`"HIGH"`, "5"}, []string{`"MEDIUM"`, "3"}),
+			facts: ages,
+		},
+		{
+			name:  "OUTPUT ORDER",
+			table: overlapping("OUTPUT ORDER", ranked, []string{`"LOW"`}, []string{`"HIGH"`}, []string{`"MEDIUM"`}),
+			facts: ages,
+		},
+		{
+			name:  "RULE ORDER",
+			table: overlapping("RULE ORDER", risk, []string{`"LOW"`}, []string{`"HIGH"`}, []string{`"MEDIUM"`}),
+			facts: ages,
+		},
+		{
+			name:  "COLLECT",
+			table: overlapping("COLLECT", risk, []string{`"LOW"`}, []string{`"HIGH"`}, []string{`"MEDIUM"`}),
+			facts: ages,
+		},
+		{
+			name:  "COLLECT SUM",
+			table: overlapping("C+", score, []string{"1"}, []string{"2"}, []string{"4"}),
+			facts: ages,
+		},
+		{
+			name:  "COLLECT MIN",
+			table: overlapping("C<", score, []string{"1"}, []string{"2"}, []string{"4"}),
+			facts: ages,
+		},
+		{
+			name:  "COLLECT MAX",
+			table: overlapping("C>", score, []string{"1"}, []string{"2"}, []string{"4"}),
+			facts: ages,
+		},
+		{
+			name:  "COLLECT COUNT writes 0 without matches",
+			table: overlapping("C#", score, []string{"1"}, []string{"2"}, []string{"4"}),
+			facts: ages,
+		},
+		{
+			name:    "COLLECT SUM of text fails",
+			table:   overlapping("SUM", risk, []string{`"LOW"`}, []string{`"HIGH"`}, []string{`"MEDIUM"`}),
+			facts:   ages,
+			failing: 4,
+		},
+		{
+			name: "array inputs hold for every element",
+			table: decisionTable("FIRST", []models.Variables{column("data.items[*].qty", "number")}, risk,
+				[]string{"> 0", `"STOCKED"`},
+				[]string{"-", `"SHORT"`}),
+			facts: []string{`{"items": [{"qty": 1}, {"qty": 2}]}`, `{"items": [{"qty": 1}, {"qty": 0}]}`, `{"items": []}`, `{}`},
+		},
+		{
+			name:  "validity windows",
+			table: dated,
+			facts: ages,
+		},
+		{
+			name: "nested output parents",
+			table: decisionTable("UNIQUE", age, []models.Variables{column("data.result.risk.level", "string"), column("data.result.score", "number")},
+				[]string{"< 30", `"LOW"`, "1"},
+				[]string{">= 30", `"HIGH"`, "2"}),
+			facts: []string{`{"age": 10}`, `{"age": 40, "result": "none"}`, `{"age": 40, "result": {"risk": 1, "kept": true}}`},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			compiled, err := Compile(c.table)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pipeline, err := ToPipeline(c.table)
+			if err != nil {
+				t.Fatal(err)
+			}
+			script := engine.GenerateScript(models.WorkflowDef{Engine: c.table.Name, Pipeline: pipeline})
+			failing := 0
+			for _, facts := range c.facts {
+				evaluated, scripted := parseFacts(t, facts), parseFacts(t, facts)
+				_, evalErr := compiled.Evaluate(context.Background(), evaluated, false)
+				scripted, _, err := engine.ExecuteContext(context.Background(), script, scripted)
+				switch {
+				case evalErr != nil && err != nil:
+					failing++
+				case evalErr != nil:
+					t.Errorf("%s: the script succeeded where Evaluate failed: %v", facts, evalErr)
+				case err != nil:
+					t.Errorf("%s: the script failed: %v", facts, err)
+				case !reflect.DeepEqual(normalize(evaluated), normalize(scripted)):
+					t.Errorf("%s: the script left %v, Evaluate %v", facts, normalize(scripted), normalize(evaluated))
+				}
+			}
+			if failing != c.failing {
+				t.Errorf("%d documents failed, expected %d", failing, c.failing)
+			}
+		})
+	}
+}
+
+func parseFacts(t *testing.T, facts string) map[string]interface{} {
+	t.Helper()
+	var out map[string]interface{}
+	if err := json.Unmarshal([]byte(facts), &out); err != nil {
+		t.Fatalf("bad facts: %v", err)
+	}
+	return out
+}
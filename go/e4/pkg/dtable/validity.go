@@ -0,0 +1,102 @@
+package dtable
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+)
+
+// validity is the period a rule row is effective in: from valid_from, inclusive, up to valid_to,
+// exclusive. Zero bounds are open, so rows without dates are always effective.
+type validity struct {
+	from, to time.Time
+}
+
+func rowValidity(table models.DecisionTable, i int) validity {
+	if i >= len(table.RulesMeta) {
+		return validity{}
+	}
+	var v validity
+	if meta := table.RulesMeta[i]; meta.ValidFrom != nil {
+		v.from = *meta.ValidFrom
+	}
+	if meta := table.RulesMeta[i]; meta.ValidTo != nil {
+		v.to = *meta.ValidTo
+	}
+	return v
+}
+
+func (v validity) dated() bool {
+	return !v.from.IsZero() || !v.to.IsZero()
+}
+
+// contains reports whether the row is effective at t
+func (v validity) contains(t time.Time) bool {
+	return (v.from.IsZero() || !t.Before(v.from)) && (v.to.IsZero() || t.Before(v.to))
+}
+
+// overlaps reports whether some moment lies in both periods
+func (v validity) overlaps(o validity) bool {
+	return (v.to.IsZero() || o.from.IsZero() || o.from.Before(v.to)) &&
+		(o.to.IsZero() || v.from.IsZero() || v.from.Before(o.to))
+}
+
+// covers reports whether every moment of o lies in v
+func (v validity) covers(o validity) bool {
+	return (v.from.IsZero() || !o.from.IsZero() && !o.from.Before(v.from)) &&
+		(v.to.IsZero() || !o.to.IsZero() && !o.to.After(v.to))
+}
+
+func (v validity) String() string {
+	switch {
+	case v.from.IsZero() && v.to.IsZero():
+		return "always"
+	case v.from.IsZero():
+		return "before " + FormatDate(v.to)
+	case v.to.IsZero():
+		return "from " + FormatDate(v.from)
+	}
+	return "from " + FormatDate(v.from) + " before " + FormatDate(v.to)
+}
+
+// checkRulesMeta returns an error when the table's rule metadata does not line up with its rows.
+// It may be left out, but otherwise holds one entry per row.
+func checkRulesMeta(table models.DecisionTable) error {
+	if len(table.RulesMeta) != 0 && len(table.RulesMeta) != len(table.Rules) {
+		return fmt.Errorf("rules_meta holds %d entries for %d rows, expected one per row", len(table.RulesMeta), len(table.Rules))
+	}
+	return nil
+}
+
+// checkValidity returns an error for a row whose valid_to does not come after its valid_from
+func checkValidity(table models.DecisionTable, i int) error {
+	v := rowValidity(table, i)
+	if !v.from.IsZero() && !v.to.IsZero() && !v.to.After(v.from) {
+		return fmt.Errorf("valid_to %s must come after valid_from %s", FormatDate(v.to), FormatDate(v.from))
+	}
+	return nil
+}
+
+// ParseDate reads an evaluation date or a row's validity bound, either a date such as 2026-01-01,
+// which starts at midnight UTC, or an RFC 3339 timestamp
+func ParseDate(text string) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	if t, err := time.Parse(time.DateOnly, text); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, text)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD or an RFC 3339 timestamp", text)
+	}
+	return t, nil
+}
+
+// FormatDate writes a time the way ParseDate reads it, as a plain date when it is midnight UTC
+func FormatDate(t time.Time) string {
+	if t.Equal(t.UTC().Truncate(24 * time.Hour)) {
+		return t.UTC().Format(time.DateOnly)
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,109 @@
+package dtable
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+)
+
+func TestParseDate(t *testing.T) {
+	for _, c := range []struct {
+		text, want string
+		err        bool
+	}{
+		{text: "2026-01-01", want: "2026-01-01"},
+		{text: " 2026-01-01 ", want: "2026-01-01"},
+		{text: "2026-01-01T00:00:00Z", want: "2026-01-01"},
+		{text: "2026-01-01T09:30:00+02:00", want: "2026-01-01T09:30:00+02:00"},
+		{text: "01/02/2026", err: true},
+		{text: "2026-02-30", err: true},
+	} {
+		got, err := ParseDate(c.text)
+		if c.err {
+			if err == nil {
+				t.Errorf("ParseDate(%q) = %v, expected an error", c.text, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseDate(%q): %v", c.text, err)
+		} else if FormatDate(got) != c.want {
+			t.Errorf("ParseDate(%q) formats as %s, expected %s", c.text, FormatDate(got), c.want)
+		}
+	}
+}
+
+// TestEffectiveRows checks that rows only match from valid_from, inclusive, up to valid_to,
+// exclusive
+func TestEffectiveRows(t *testing.T) {
+	table := decisionTable("FIRST", []models.Variables{column("data.age", "number")}, []models.Variables{column("data.rate", "number")},
+		[]string{"< 25", "5"},
+		[]string{"< 25", "6"},
+		[]string{"-", "9"})
+	effective(t, &table, 0, "", "2025-01-01")
+	effective(t, &table, 1, "2025-01-01", "2026-01-01T12:00:00Z")
+	compiled, err := Compile(table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		at      string
+		matched []int
+	}{
+		{"2024-12-31T23:59:59Z", []int{1}},
+		{"2025-01-01", []int{2}},
+		{"2026-01-01T11:59:59Z", []int{2}},
+		{"2026-01-01T12:00:00Z", []int{3}},
+	} {
+		result, err := compiled.EvaluateAt(context.Background(), map[string]interface{}{"age": 20.0}, *date(t, c.at), false)
+		if err != nil {
+			t.Errorf("at %s: %v", c.at, err)
+		} else if len(result.MatchedRows) != 1 || result.MatchedRows[0] != c.matched[0] {
+			t.Errorf("at %s matched rows %v, expected %v", c.at, result.MatchedRows, c.matched)
+		}
+	}
+}
+
+func TestCheckCellsRejectsEmptyPeriods(t *testing.T) {
+	table := decisionTable("UNIQUE", []models.Variables{column("data.age", "number")}, []models.Variables{column("data.rate", "number")},
+		[]string{"< 25", "5"},
+		[]string{">= 25", "6"},
+		[]string{"[1..", "7"})
+	effective(t, &table, 0, "2025-01-01", "2024-01-01")
+	effective(t, &table, 1, "2025-01-01", "2025-01-01")
+	problems := CheckCells(table)
+	if len(problems) != 3 {
+		t.Fatalf("problems %v, expected the two empty periods and the bad entry", problems)
+	}
+	for i, want := range []struct {
+		row    int
+		column string
+	}{{1, "valid_to"}, {2, "valid_to"}, {3, "data.age"}} {
+		if problems[i].Row != want.row || problems[i].Column != want.column {
+			t.Errorf("problem %d is %v, expected row %d, %s", i, problems[i], want.row, want.column)
+		}
+	}
+	if _, err := time.Parse(time.DateOnly, problems[0].Entry); err != nil {
+		t.Errorf("problem entry %q is not the valid_to date", problems[0].Entry)
+	}
+}
+
+func TestCheckCellsRejectsMisalignedRulesMeta(t *testing.T) {
+	table := decisionTable("UNIQUE", []models.Variables{column("data.age", "number")}, []models.Variables{column("data.rate", "number")},
+		[]string{"< 25", "5"},
+		[]string{">= 25", "6"})
+	table.RulesMeta = []models.RuleMeta{{ValidFrom: date(t, "2025-01-01")}}
+	problems := CheckCells(table)
+	if len(problems) != 1 || problems[0].Column != "rules_meta" {
+		t.Fatalf("problems %v, expected the misaligned rules_meta", problems)
+	}
+	if _, err := Compile(table); err == nil {
+		t.Error("expected Compile to reject the misaligned rules_meta")
+	}
+	table.RulesMeta = nil
+	if problems := CheckCells(table); len(problems) != 0 {
+		t.Errorf("problems %v, expected none without rules_meta", problems)
+	}
+}
@@ -0,0 +1,149 @@
+package dtable
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+)
+
+// Root of every variable key, e.g. data.applicant.age
+const factsRoot = "data"
+
+// lookup reads the value at a dotted variable key from the facts. Keys with [*] collect the value
+// of every array element and report found only when the path reaches an array.
+func lookup(facts map[string]interface{}, varKey string) (interface{}, bool) {
+	path := strings.TrimPrefix(varKey, factsRoot+".")
+	if head, rest, isArray := strings.Cut(path, "[*]"); isArray {
+		arr, ok := walk(facts, head)
+		elems, isSlice := arr.([]interface{})
+		if !ok || !isSlice {
+			return nil, false
+		}
+		rest = strings.TrimPrefix(rest, ".")
+		values := make([]interface{}, 0, len(elems))
+		for _, elem := range elems {
+			if rest == "" {
+				values = append(values, elem)
+				continue
+			}
+			doc, _ := elem.(map[string]interface{})
+			value, _ := lookup(doc, rest)
+			values = append(values, value)
+		}
+		return values, true
+	}
+	return walk(facts, path)
+}
+
+func walk(facts map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = facts
+	for _, name := range strings.Split(path, ".") {
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = obj[name]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// assign writes value at a dotted variable key, creating the intermediate objects
+func assign(facts map[string]interface{}, varKey string, value interface{}) {
+	names := strings.Split(strings.TrimPrefix(varKey, factsRoot+"."), ".")
+	current := facts
+	for _, name := range names[:len(names)-1] {
+		next, ok := current[name].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			current[name] = next
+		}
+		current = next
+	}
+	current[names[len(names)-1]] = value
+}
+
+// parseOutput converts an output entry into a typed value using the output column's type.
+// Untyped columns infer numbers, booleans, quoted strings and JSON objects or arrays.
+func parseOutput(column models.Variables, entry interface{}) interface{} {
+	raw, ok := entry.(string)
+	if !ok {
+		return entry
+	}
+	raw = strings.TrimSpace(raw)
+	if raw == "" || strings.EqualFold(raw, "null") {
+		return nil
+	}
+	switch column.Type {
+	case "string":
+		return unquote(raw)
+	case "number":
+		if f, err := strconv.ParseFloat(raw, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(raw); err == nil {
+			return b
+		}
+	}
+	if f, err := strconv.ParseFloat(raw, 64); err == nil {
+		return f
+	}
+	if b, err := strconv.ParseBool(raw); err == nil {
+		return b
+	}
+	if raw[0] == '{' || raw[0] == '[' {
+		var doc interface{}
+		if err := json.Unmarshal([]byte(raw), &doc); err == nil {
+			return doc
+		}
+	}
+	return unquote(raw)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// formatValue renders a fact value the way cells spell it
+func formatValue(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(t)
+	default:
+		if f, ok := toFloat(v); ok {
+			return strconv.FormatFloat(f, 'f', -1, 64)
+		}
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(raw)
+	}
+}
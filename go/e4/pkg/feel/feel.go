@@ -0,0 +1,98 @@
+// Package feel parses and evaluates the unary tests of DMN 1.3 FEEL, the language of decision
+// table input entries. An entry is one of
+//
+//	null                        no value
+//	-                           any value, as is a blank entry
+//	5, "gold", true             equality with a literal
+//	< 10, >= date("2024-01-01") comparisons, also = and !=
+//	[1..10), ]0..1], (0..5)     intervals with open or closed bounds
+//	"a", [1..5], > 100          lists, matching when any test matches
+//	not("a", "b")               negation of a list
+//
+// Literals are numbers, strings, booleans and the temporal literals date("..."), time("..."),
+// date and time("...") and @"...". Names and other expressions are not supported.
+//
+// Tests follow FEEL's three-valued logic: comparing values of different types, or comparing null
+// with <, gives null rather than false, so not(< 10) does not match a missing value. A value only
+// matches when its tests give true. Facts come from JSON, which has no temporal type, so strings
+// are read as dates and times when compared with a temporal literal.
+//
+// Core keeps a copy of this package without js.go and the js fields of tests; a fix to the other
+// files belongs in both copies, which TestCoreCopies in dtable compares.
+package feel
+
+import (
+	"fmt"
+)
+
+// UnaryTests is a parsed input entry
+type UnaryTests struct {
+	Text    string
+	any     bool
+	negated bool
+	tests   []test
+}
+
+// test is one positive unary test. eval returns nil for FEEL's null; ranges are the values of
+// the literal's domain for which it gives true, and domain is empty for the null test. js writes
+// the JavaScript conditions under which it gives true and false.
+type test struct {
+	eval   func(value interface{}) *bool
+	domain string
+	ranges []interval
+	js     func(value string) (isTrue, isFalse string)
+}
+
+// SyntaxError is an entry that is not valid FEEL. Offset is the byte offset of the problem.
+type SyntaxError struct {
+	Text    string
+	Offset  int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at offset %d of %q", e.Message, e.Offset, e.Text)
+}
+
+// Parse parses an input entry. A blank entry is the same as "-".
+func Parse(text string) (*UnaryTests, error) {
+	p := &parser{text: text}
+	if err := p.scan(); err != nil {
+		return nil, err
+	}
+	return p.parseEntry()
+}
+
+// Match reports whether the tests give true for value
+func (u *UnaryTests) Match(value interface{}) bool {
+	if u.any {
+		return true
+	}
+	result := disjunction(u.tests, value)
+	if u.negated && result != nil {
+		flipped := !*result
+		result = &flipped
+	}
+	return result != nil && *result
+}
+
+// disjunction is FEEL's or over a list of tests: true if any test is true, else null if any is null
+func disjunction(tests []test, value interface{}) *bool {
+	sawNull := false
+	for _, t := range tests {
+		result := t.eval(value)
+		if result == nil {
+			sawNull = true
+		} else if *result {
+			return result
+		}
+	}
+	if sawNull {
+		return nil
+	}
+	return boolean(false)
+}
+
+func boolean(b bool) *bool {
+	return &b
+}
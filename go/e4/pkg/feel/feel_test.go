@@ -0,0 +1,195 @@
+package feel
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/dop251/goja"
+)
+
+// matchCases pair an entry with a fact value and whether the entry matches it. A value the
+// entry's tests give null for never matches, negated or not.
+var matchCases = []struct {
+	entry string
+	value interface{}
+	want  bool
+}{
+	// Any value
+	{"-", nil, true},
+	{"-", 5.0, true},
+	{"", "x", true},
+	{"  ", nil, true},
+
+	// Equality with literals
+	{"5", 5.0, true},
+	{"5", 6.0, false},
+	{"5", "5", false},
+	{"5", nil, false},
+	{"-2.5", -2.5, true},
+	{`"gold"`, "gold", true},
+	{`"gold"`, "GOLD", false},
+	{`"say \"hi\""`, `say "hi"`, true},
+	{"true", true, true},
+	{"true", false, false},
+	{"true", "true", false},
+
+	// Comparisons
+	{"< 10", 9.0, true},
+	{"< 10", 10.0, false},
+	{"<= 10", 10.0, true},
+	{"> 10", 10.0, false},
+	{">= 10", 10.0, true},
+	{"= 5", 5.0, true},
+	{"!= 5", 4.0, true},
+	{"!= 5", 5.0, false},
+	{"< 10", "9", false},
+	{`> "m"`, "z", true},
+	{`> "m"`, "a", false},
+	{"= true", true, true},
+	{"!= true", false, true},
+
+	// Intervals with closed and open bounds, ( ) and ] [ both open
+	{"[1..10]", 1.0, true},
+	{"[1..10]", 10.0, true},
+	{"[1..10]", 0.0, false},
+	{"(1..10)", 1.0, false},
+	{"(1..10)", 5.0, true},
+	{"(1..10)", 10.0, false},
+	{"]1..10[", 1.0, false},
+	{"]1..10[", 9.5, true},
+	{"]1..10[", 10.0, false},
+	{"[1..10)", 1.0, true},
+	{"[1..10)", 10.0, false},
+	{"(1..10]", 10.0, true},
+	{"[-5..-1]", -3.0, true},
+	{"[.5..1.5]", 1.5, true},
+	{"[.5..1.5]", 0.4, false},
+	{`["a".."c"]`, "b", true},
+	{"[1..10]", "5", false},
+	{"[1..10]", nil, false},
+	{`[1.."b"]`, 1.0, false},
+
+	// Lists match when any test matches
+	{`"IN","US"`, "US", true},
+	{`"IN","US"`, "FR", false},
+	{`"a", [1..5], > 100`, 3.0, true},
+	{`"a", [1..5], > 100`, 101.0, true},
+	{`"a", [1..5], > 100`, "a", true},
+	{`"a", [1..5], > 100`, 50.0, false},
+
+	// not(...) negates the list, keeping null as null
+	{`not("a", "b")`, "c", true},
+	{`not("a", "b")`, "a", false},
+	{`not("a")`, nil, true},
+	{"not(< 10)", 15.0, true},
+	{"not(< 10)", 5.0, false},
+	{"not(< 10)", nil, false},
+	{"not(< 10)", "x", false},
+	{"not(5)", "5", false},
+	{"not([1..5], 9)", 7.0, true},
+	{"not([1..5], 9)", 9.0, false},
+
+	// null and three-valued logic
+	{"null", nil, true},
+	{"null", 0.0, false},
+	{"null", "", false},
+	{"not(null)", nil, false},
+	{"not(null)", 5.0, true},
+	{"null, > 5", nil, true},
+	{"null, > 5", 6.0, true},
+	{"null, > 5", 4.0, false},
+	{"!= 5", nil, true},
+	{"!= 5", "x", false},
+
+	// Dates, times and dates and times, read from strings
+	{`date("2024-01-01")`, "2024-01-01", true},
+	{`date("2024-01-01")`, "2024-01-02", false},
+	{`date("2024-01-01")`, "2024-01-01T10:00:00Z", false},
+	{`> date("2024-01-01")`, "2024-06-30", true},
+	{`> date("2024-01-01")`, "2023-12-31", false},
+	{`> date("2024-01-01")`, "not a date", false},
+	{`> date("2024-01-01")`, 20240101.0, false},
+	{`[date("2024-01-01")..date("2024-12-31")]`, "2024-12-31", true},
+	{`[date("2024-01-01")..date("2024-12-31")]`, "2025-01-01", false},
+	{`@"2024-01-01"`, "2024-01-01", true},
+	{`< time("12:00")`, "09:30", true},
+	{`< time("12:00")`, "13:00:00", false},
+	{`>= date and time("2024-01-01T00:00:00Z")`, "2024-01-01T01:00:00+01:00", true},
+	{`>= date and time("2024-01-01T00:00:00Z")`, "2023-12-31T23:59:59Z", false},
+	{`not(< date("2024-01-01"))`, nil, false},
+}
+
+// TestMatch evaluates every case with Match and with the JavaScript of the entry, which pipelines
+// compiled from decision tables run, so both follow the same semantics
+func TestMatch(t *testing.T) {
+	for _, c := range matchCases {
+		entry, err := Parse(c.entry)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", c.entry, err)
+			continue
+		}
+		if got := entry.Match(c.value); got != c.want {
+			t.Errorf("%q matching %#v = %t, expected %t", c.entry, c.value, got, c.want)
+		}
+		if got, err := matchJS(entry, c.value); err != nil {
+			t.Errorf("%q: JavaScript %s fails: %v", c.entry, entry.JS("v"), err)
+		} else if got != c.want {
+			t.Errorf("%q matching %#v in JavaScript = %t, expected %t: %s", c.entry, c.value, got, c.want, entry.JS("v"))
+		}
+	}
+}
+
+// matchJS runs the JavaScript of the entry on value in goja
+func matchJS(entry *UnaryTests, value interface{}) (bool, error) {
+	literal, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+	script := "var " + JSTimeHelper + " = " + JSTimeHelperSource + "; var v = " + string(literal) + "; " + entry.JS("v")
+	result, err := goja.New().RunString(script)
+	if err != nil {
+		return false, err
+	}
+	return result.ToBoolean(), nil
+}
+
+// TestSyntaxErrors checks that invalid entries fail with a *SyntaxError at the offending offset
+func TestSyntaxErrors(t *testing.T) {
+	for _, c := range []struct {
+		entry  string
+		offset int
+	}{
+		{"<", 1},
+		{"< ", 2},
+		{"[1..10", 6},
+		{"[1 10]", 3},
+		{"(1..", 4},
+		{"foo", 0},
+		{`"a", gold`, 5},
+		{`"open`, 0},
+		{`"bad \q"`, 5},
+		{"< true", 2},
+		{"[true..false]", 0},
+		{`date("2024-13-01")`, 5},
+		{`date(2024)`, 5},
+		{`date and("2024-01-01")`, 8},
+		{`@"tomorrow"`, 1},
+		{"@5", 1},
+		{"1 2", 2},
+		{"not(1", 5},
+		{"not(1))", 6},
+		{"5 & 6", 2},
+		{"1,", 2},
+	} {
+		_, err := Parse(c.entry)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("Parse(%q) = %v, expected a syntax error", c.entry, err)
+			continue
+		}
+		if syntaxErr.Offset != c.offset {
+			t.Errorf("Parse(%q) failed at offset %d, expected %d: %v", c.entry, syntaxErr.Offset, c.offset, err)
+		}
+	}
+}
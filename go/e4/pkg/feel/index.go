@@ -0,0 +1,74 @@
+package feel
+
+import "math"
+
+// Range is an interval of numbers; unbounded ends are infinite
+type Range struct {
+	Lo, Hi         float64
+	LoOpen, HiOpen bool
+}
+
+// Contains reports whether n lies in the range
+func (r Range) Contains(n float64) bool {
+	return (r.Lo < n || (r.Lo == n && !r.LoOpen)) && (n < r.Hi || (n == r.Hi && !r.HiOpen))
+}
+
+// Points returns the values the entry matches when they are a finite list of numbers, strings or
+// booleans, such as "GOLD","SILVER" or 5. Entries matching null or anything else return false.
+func (u *UnaryTests) Points() ([]interface{}, bool) {
+	if !u.indexable() {
+		return nil, false
+	}
+	var points []interface{}
+	for _, t := range u.tests {
+		if t.domain != domainNumber && t.domain != domainString && t.domain != domainBoolean {
+			return nil, false
+		}
+		for _, iv := range t.ranges {
+			if iv.lo.inf || iv.hi.inf || iv.lo.open || iv.hi.open || !pointValue(iv) {
+				return nil, false
+			}
+			points = append(points, iv.lo.value)
+		}
+	}
+	return points, true
+}
+
+// NumberRanges returns the numbers the entry matches when it only matches numbers, such as
+// [18..65) or < 0, > 100
+func (u *UnaryTests) NumberRanges() ([]Range, bool) {
+	if !u.indexable() {
+		return nil, false
+	}
+	var ranges []interval
+	for _, t := range u.tests {
+		if t.domain != domainNumber {
+			return nil, false
+		}
+		ranges = union(ranges, t.ranges)
+	}
+	out := make([]Range, len(ranges))
+	for i, iv := range ranges {
+		out[i] = Range{Lo: math.Inf(-1), Hi: math.Inf(1), LoOpen: iv.lo.open, HiOpen: iv.hi.open}
+		if !iv.lo.inf {
+			out[i].Lo = iv.lo.value.(float64)
+		}
+		if !iv.hi.inf {
+			out[i].Hi = iv.hi.value.(float64)
+		}
+	}
+	return out, true
+}
+
+// indexable reports whether the entry is a plain list of tests that null does not match, whose
+// ranges then describe exactly the values it matches
+func (u *UnaryTests) indexable() bool {
+	return !u.any && !u.negated && len(u.tests) > 0 && !u.Match(nil)
+}
+
+func pointValue(iv interval) bool {
+	if b, ok := iv.lo.value.(bool); ok {
+		return b == iv.hi.value.(bool)
+	}
+	return cmpValue(iv.lo.value, iv.hi.value) == 0
+}
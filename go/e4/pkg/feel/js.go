@@ -0,0 +1,109 @@
+package feel
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JSTimeHelper is the name under which scripts using JS must define JSTimeHelperSource, a function
+// reading a fact string as the milliseconds of a temporal kind, or null
+const JSTimeHelper = "__feel_time"
+
+// JSTimeHelperSource parses strings with the layouts the Go evaluator accepts. Times are read on
+// 1970-01-01 and values without a zone as UTC, matching the milliseconds of temporal literals. It
+// is one line, as pipeline statements are echoed into single-line log messages.
+const JSTimeHelperSource = `function (v, kind) { ` +
+	`if (typeof v !== "string") { return null; } ` +
+	`var zone = /(Z|[+-]\d{2}:\d{2})$/.test(v) ? "" : "Z"; ` +
+	`var ms = NaN; ` +
+	`if (kind === "date" && /^\d{4}-\d{2}-\d{2}$/.test(v)) { ms = Date.parse(v + "T00:00:00Z"); } ` +
+	`if (kind === "time" && /^\d{2}:\d{2}(:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})?)?$/.test(v)) { ms = Date.parse("1970-01-01T" + v + zone); } ` +
+	`if (kind === "date and time" && /^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}(:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})?)?$/.test(v)) { ms = Date.parse(v + zone); } ` +
+	`return isNaN(ms) ? null : ms; }`
+
+// JS returns a JavaScript expression that is true when the value of the expression value matches
+// the entry, following the same three-valued logic as Match. value is evaluated several times, so
+// it should be a variable. Temporal tests call JSTimeHelper.
+func (u *UnaryTests) JS(value string) string {
+	if u.any {
+		return "true"
+	}
+	parts := make([]string, len(u.tests))
+	if u.negated {
+		for i, t := range u.tests {
+			_, parts[i] = t.js(value)
+		}
+		return "(" + strings.Join(parts, " && ") + ")"
+	}
+	for i, t := range u.tests {
+		parts[i], _ = t.js(value)
+	}
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return "(" + strings.Join(parts, " || ") + ")"
+}
+
+func nullJS(value string) (string, string) {
+	return "(" + value + " == null)", "(" + value + " != null)"
+}
+
+// comparisonJS returns the conditions under which value op literal is true and false. Values of
+// another type give neither, as they give null in Go; null is only definite for = and !=.
+func comparisonJS(op string, literal interface{}) func(value string) (string, string) {
+	return func(value string) (string, string) {
+		guard, operand, lit := "", value, ""
+		switch l := literal.(type) {
+		case float64:
+			guard, lit = "typeof "+value+` === "number"`, strconv.FormatFloat(l, 'g', -1, 64)
+		case string:
+			guard, lit = "typeof "+value+` === "string"`, JSString(l)
+		case bool:
+			guard, lit = "typeof "+value+` === "boolean"`, strconv.FormatBool(l)
+		case temporal:
+			operand = JSTimeHelper + "(" + value + ", " + JSString(l.kind) + ")"
+			guard, lit = operand+" !== null", strconv.FormatInt(temporalMillis(l), 10)
+		}
+		jsOp := map[string]string{"=": "===", "!=": "!=="}[op]
+		if jsOp == "" {
+			jsOp = op
+		}
+		cmp := operand + " " + jsOp + " " + lit
+		isTrue := "(" + guard + " && " + cmp + ")"
+		isFalse := "(" + guard + " && !(" + cmp + "))"
+		switch op {
+		case "=":
+			isFalse = "(" + value + " == null || " + isFalse + ")"
+		case "!=":
+			isTrue = "(" + value + " == null || " + isTrue + ")"
+		}
+		return isTrue, isFalse
+	}
+}
+
+// intervalJS combines the tests of both bounds with FEEL's and: true when both are true, false
+// when either is false
+func intervalJS(above, below test) func(value string) (string, string) {
+	return func(value string) (string, string) {
+		aboveTrue, aboveFalse := above.js(value)
+		belowTrue, belowFalse := below.js(value)
+		return "(" + aboveTrue + " && " + belowTrue + ")", "(" + aboveFalse + " || " + belowFalse + ")"
+	}
+}
+
+// temporalMillis converts a literal to the milliseconds JSTimeHelperSource computes
+func temporalMillis(t temporal) int64 {
+	if t.kind == kindTime {
+		return t.value.Sub(time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC)).Milliseconds()
+	}
+	return t.value.UnixMilli()
+}
+
+// JSString quotes s as a JavaScript string literal without single quotes, so that it can also
+// be embedded in single-quoted log messages
+func JSString(s string) string {
+	quoted, _ := json.Marshal(s)
+	return strings.ReplaceAll(string(quoted), "'", `\u0027`)
+}
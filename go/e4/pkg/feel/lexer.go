@@ -0,0 +1,138 @@
+package feel
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenName
+	tokenPunct
+)
+
+type token struct {
+	kind   tokenKind
+	text   string // punctuation, name or number as written; the decoded value of a string
+	offset int
+}
+
+type parser struct {
+	text   string
+	tokens []token
+	pos    int
+}
+
+// Punctuation, longest first so that <= is not read as <
+var punctuation = []string{"..", "<=", ">=", "!=", "<", ">", "=", "[", "]", "(", ")", ",", "-", "@"}
+
+func (p *parser) scan() error {
+	for i := 0; i < len(p.text); {
+		r, size := utf8.DecodeRuneInString(p.text[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '"':
+			value, end, err := p.scanString(i)
+			if err != nil {
+				return err
+			}
+			p.tokens = append(p.tokens, token{kind: tokenString, text: value, offset: i})
+			i = end
+		case isDigit(r) || (r == '.' && i+1 < len(p.text) && isDigit(rune(p.text[i+1]))):
+			end := i
+			for end < len(p.text) && isDigit(rune(p.text[end])) {
+				end++
+			}
+			// A dot starts a fraction unless it is the .. of an interval
+			if end+1 < len(p.text) && p.text[end] == '.' && isDigit(rune(p.text[end+1])) {
+				end++
+				for end < len(p.text) && isDigit(rune(p.text[end])) {
+					end++
+				}
+			}
+			p.tokens = append(p.tokens, token{kind: tokenNumber, text: p.text[i:end], offset: i})
+			i = end
+		case unicode.IsLetter(r) || r == '_' || r == '?':
+			end := i + size
+			for end < len(p.text) {
+				next, nextSize := utf8.DecodeRuneInString(p.text[end:])
+				if !unicode.IsLetter(next) && !unicode.IsDigit(next) && next != '_' {
+					break
+				}
+				end += nextSize
+			}
+			p.tokens = append(p.tokens, token{kind: tokenName, text: p.text[i:end], offset: i})
+			i = end
+		default:
+			matched := false
+			for _, punct := range punctuation {
+				if strings.HasPrefix(p.text[i:], punct) {
+					p.tokens = append(p.tokens, token{kind: tokenPunct, text: punct, offset: i})
+					i += len(punct)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return &SyntaxError{Text: p.text, Offset: i, Message: "unexpected character " + string(r)}
+			}
+		}
+	}
+	p.tokens = append(p.tokens, token{kind: tokenEOF, offset: len(p.text)})
+	return nil
+}
+
+// scanString decodes the string literal starting at start, returning its value and end offset
+func (p *parser) scanString(start int) (string, int, error) {
+	var value strings.Builder
+	for i := start + 1; i < len(p.text); i++ {
+		switch p.text[i] {
+		case '"':
+			return value.String(), i + 1, nil
+		case '\\':
+			if i+1 >= len(p.text) {
+				break
+			}
+			i++
+			switch p.text[i] {
+			case '"', '\\', '\'':
+				value.WriteByte(p.text[i])
+			case 'n':
+				value.WriteByte('\n')
+			case 't':
+				value.WriteByte('\t')
+			case 'r':
+				value.WriteByte('\r')
+			case 'u':
+				if i+4 >= len(p.text) {
+					return "", 0, &SyntaxError{Text: p.text, Offset: i - 1, Message: "incomplete unicode escape"}
+				}
+				var r rune
+				for _, h := range p.text[i+1 : i+5] {
+					digit := strings.IndexRune("0123456789abcdef", unicode.ToLower(h))
+					if digit < 0 {
+						return "", 0, &SyntaxError{Text: p.text, Offset: i - 1, Message: "invalid unicode escape"}
+					}
+					r = r*16 + rune(digit)
+				}
+				value.WriteRune(r)
+				i += 4
+			default:
+				return "", 0, &SyntaxError{Text: p.text, Offset: i - 1, Message: "invalid escape \\" + string(p.text[i])}
+			}
+		default:
+			value.WriteByte(p.text[i])
+		}
+	}
+	return "", 0, &SyntaxError{Text: p.text, Offset: start, Message: "unterminated string"}
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
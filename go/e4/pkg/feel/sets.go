@@ -0,0 +1,432 @@
+package feel
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Domains of values a Set ranges over. Values of the any domain are the ones no literal can
+// name, such as objects; only "-" and not(null) match them.
+const (
+	domainNumber  = "number"
+	domainString  = "string"
+	domainBoolean = "boolean"
+	domainAny     = "any"
+)
+
+// Order of the domains in descriptions
+var domains = []string{domainNumber, domainString, kindDate, kindTime, kindDateTime, domainAny}
+
+// bound is an end of an interval; inf marks -∞ for a lower bound and +∞ for an upper one
+type bound struct {
+	value interface{}
+	open  bool
+	inf   bool
+}
+
+type interval struct {
+	lo, hi bound
+}
+
+// Set is the set of values for which an entry gives true, for static analysis of tables. Values
+// of different domains are distinct, so the string "2024-01-01" and date("2024-01-01") do not
+// overlap even though a string fact may match both.
+type Set struct {
+	all     bool
+	null    bool
+	isTrue  bool
+	isFalse bool
+	ranges  map[string][]interval // domain -> sorted, disjoint, non-empty intervals
+}
+
+// Set returns the values the entry matches
+func (u *UnaryTests) Set() Set {
+	if u.any {
+		return Set{all: true}
+	}
+	s := Set{null: u.Match(nil), isTrue: u.Match(true), isFalse: u.Match(false), ranges: map[string][]interval{}}
+	for _, t := range u.tests {
+		if t.domain != "" && t.domain != domainBoolean {
+			s.ranges[t.domain] = union(s.ranges[t.domain], t.ranges)
+		}
+	}
+	if !u.negated {
+		return s
+	}
+	// Values of a domain a test cannot compare with make that test null, and not(null) is null,
+	// so a negated list only matches values of the one domain all its tests compare with. The
+	// null test is false for every value and does not count.
+	domain, mixed := "", false
+	for _, t := range u.tests {
+		if t.domain == "" {
+			continue
+		}
+		if domain != "" && t.domain != domain {
+			mixed = true
+		}
+		domain = t.domain
+	}
+	negated := map[string][]interval{}
+	switch {
+	case domain == "":
+		for _, d := range domains {
+			negated[d] = full()
+		}
+	case !mixed && domain != domainBoolean:
+		negated[domain] = complement(s.ranges[domain])
+	}
+	s.ranges = negated
+	return s
+}
+
+// LiteralSet returns the set holding only value, for cells stored as a boolean or number rather than an entry
+func LiteralSet(value interface{}) Set {
+	s := Set{ranges: map[string][]interval{}}
+	switch v := value.(type) {
+	case bool:
+		s.isTrue, s.isFalse = v, !v
+	case nil:
+		s.null = true
+	default:
+		if n, ok := toNumber(v); ok {
+			s.ranges[domainNumber] = []interval{pointInterval(n)}
+		}
+	}
+	return s
+}
+
+// Universe returns the non-null values a column of the given type can hold. Columns without a
+// known type hold the domains their cells mention, or any value when they mention none.
+func Universe(columnType string, cells []Set) Set {
+	s := Set{ranges: map[string][]interval{}}
+	switch columnType {
+	case "boolean":
+		s.isTrue, s.isFalse = true, true
+	case "number", "string", kindDate, kindTime:
+		s.ranges[columnType] = full()
+	case "dateTime", "datetime", kindDateTime:
+		s.ranges[kindDateTime] = full()
+	default:
+		for _, cell := range cells {
+			s.isTrue = s.isTrue || cell.isTrue
+			s.isFalse = s.isFalse || cell.isFalse
+			for domain, ranges := range cell.ranges {
+				if len(ranges) > 0 {
+					s.ranges[domain] = full()
+				}
+			}
+		}
+		if len(s.ranges) == 0 && !s.isTrue && !s.isFalse {
+			s.ranges[domainAny] = full()
+		}
+	}
+	return s
+}
+
+// Intersect returns the values in both sets
+func (s Set) Intersect(o Set) Set {
+	if s.all {
+		return o
+	}
+	if o.all {
+		return s
+	}
+	out := Set{null: s.null && o.null, isTrue: s.isTrue && o.isTrue, isFalse: s.isFalse && o.isFalse, ranges: map[string][]interval{}}
+	for domain, ranges := range s.ranges {
+		if both := intersect(ranges, o.ranges[domain]); len(both) > 0 {
+			out.ranges[domain] = both
+		}
+	}
+	return out
+}
+
+// Subtract returns the values of s that are not in o. Neither set may be "-"; intersect them with
+// a universe first.
+func (s Set) Subtract(o Set) Set {
+	out := Set{null: s.null && !o.null, isTrue: s.isTrue && !o.isTrue, isFalse: s.isFalse && !o.isFalse, ranges: map[string][]interval{}}
+	for domain, ranges := range s.ranges {
+		if rest := intersect(ranges, complement(o.ranges[domain])); len(rest) > 0 {
+			out.ranges[domain] = rest
+		}
+	}
+	return out
+}
+
+// IsEmpty reports whether the set holds no value
+func (s Set) IsEmpty() bool {
+	if s.all || s.null || s.isTrue || s.isFalse {
+		return false
+	}
+	for _, ranges := range s.ranges {
+		if len(ranges) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal reports whether both sets hold the same values
+func (s Set) Equal(o Set) bool {
+	if s.all || o.all {
+		return s.all == o.all
+	}
+	if s.null != o.null || s.isTrue != o.isTrue || s.isFalse != o.isFalse {
+		return false
+	}
+	for _, domain := range domains {
+		a, b := s.ranges[domain], o.ranges[domain]
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !sameBound(a[i].lo, b[i].lo) || !sameBound(a[i].hi, b[i].hi) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Describe writes the set as a FEEL entry, "-" when it is the whole universe
+func (s Set) Describe(universe Set) string {
+	if s.all || s.Equal(universe) {
+		return "-"
+	}
+	var parts []string
+	if s.null {
+		parts = append(parts, "null")
+	}
+	if s.isTrue {
+		parts = append(parts, "true")
+	}
+	if s.isFalse {
+		parts = append(parts, "false")
+	}
+	for _, domain := range domains {
+		ranges := s.ranges[domain]
+		if len(ranges) == 0 {
+			continue
+		}
+		if domain == domainAny {
+			parts = append(parts, "any other value")
+			continue
+		}
+		// All but a few points reads better as not(...)
+		if excluded := complement(ranges); len(excluded) > 0 && allPoints(excluded) {
+			var points []string
+			for _, iv := range excluded {
+				points = append(points, literalText(iv.lo.value))
+			}
+			parts = append(parts, "not("+strings.Join(points, ", ")+")")
+			continue
+		}
+		for _, iv := range ranges {
+			parts = append(parts, describeInterval(domain, iv))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func describeInterval(domain string, iv interval) string {
+	switch {
+	case iv.lo.inf && iv.hi.inf:
+		return "any " + domain
+	case iv.lo.inf:
+		return map[bool]string{true: "< ", false: "<= "}[iv.hi.open] + literalText(iv.hi.value)
+	case iv.hi.inf:
+		return map[bool]string{true: "> ", false: ">= "}[iv.lo.open] + literalText(iv.lo.value)
+	case !iv.lo.open && !iv.hi.open && cmpValue(iv.lo.value, iv.hi.value) == 0:
+		return literalText(iv.lo.value)
+	}
+	open, closing := "[", "]"
+	if iv.lo.open {
+		open = "("
+	}
+	if iv.hi.open {
+		closing = ")"
+	}
+	return open + literalText(iv.lo.value) + ".." + literalText(iv.hi.value) + closing
+}
+
+func literalText(value interface{}) string {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return strconv.Quote(v)
+	case temporal:
+		switch v.kind {
+		case kindDate:
+			return `date("` + v.value.Format("2006-01-02") + `")`
+		case kindTime:
+			return `time("` + v.value.Format("15:04:05.999999999") + `")`
+		}
+		return `date and time("` + v.value.Format(time.RFC3339Nano) + `")`
+	}
+	return ""
+}
+
+// rangesFor returns the values of the literal's domain for which value op literal is true
+func rangesFor(op string, literal interface{}) []interval {
+	switch op {
+	case "=":
+		return []interval{pointInterval(literal)}
+	case "!=":
+		return complement([]interval{pointInterval(literal)})
+	case "<", "<=":
+		return []interval{{lo: bound{inf: true}, hi: bound{value: literal, open: op == "<"}}}
+	default:
+		return []interval{{lo: bound{value: literal, open: op == ">"}, hi: bound{inf: true}}}
+	}
+}
+
+func domainOf(literal interface{}) string {
+	switch l := literal.(type) {
+	case float64:
+		return domainNumber
+	case string:
+		return domainString
+	case bool:
+		return domainBoolean
+	case temporal:
+		return l.kind
+	}
+	return ""
+}
+
+func full() []interval {
+	return []interval{{lo: bound{inf: true}, hi: bound{inf: true}}}
+}
+
+func pointInterval(value interface{}) interval {
+	return interval{lo: bound{value: value}, hi: bound{value: value}}
+}
+
+func allPoints(ranges []interval) bool {
+	for _, iv := range ranges {
+		if iv.lo.inf || iv.hi.inf || iv.lo.open || iv.hi.open || cmpValue(iv.lo.value, iv.hi.value) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// cmpValue orders two literals of the same domain
+func cmpValue(a, b interface{}) int {
+	switch x := a.(type) {
+	case float64:
+		y := b.(float64)
+		return order(x < y, x > y)
+	case string:
+		return strings.Compare(x, b.(string))
+	case temporal:
+		y := b.(temporal)
+		return order(x.value.Before(y.value), x.value.After(y.value))
+	}
+	return 0
+}
+
+func sameBound(a, b bound) bool {
+	if a.inf || b.inf {
+		return a.inf == b.inf
+	}
+	return a.open == b.open && cmpValue(a.value, b.value) == 0
+}
+
+// lowerBefore orders lower bounds: -∞ first, then by value, a closed bound before an open one
+func lowerBefore(a, b bound) bool {
+	if a.inf || b.inf {
+		return a.inf && !b.inf
+	}
+	if c := cmpValue(a.value, b.value); c != 0 {
+		return c < 0
+	}
+	return !a.open && b.open
+}
+
+// upperBefore orders upper bounds: by value, an open bound before a closed one, then +∞
+func upperBefore(a, b bound) bool {
+	if a.inf || b.inf {
+		return b.inf && !a.inf
+	}
+	if c := cmpValue(a.value, b.value); c != 0 {
+		return c < 0
+	}
+	return a.open && !b.open
+}
+
+func empty(iv interval) bool {
+	if iv.lo.inf || iv.hi.inf {
+		return false
+	}
+	c := cmpValue(iv.lo.value, iv.hi.value)
+	return c > 0 || (c == 0 && (iv.lo.open || iv.hi.open))
+}
+
+// joins reports whether b, starting no earlier than a, overlaps or touches a
+func joins(a, b interval) bool {
+	if a.hi.inf || b.lo.inf {
+		return true
+	}
+	c := cmpValue(b.lo.value, a.hi.value)
+	return c < 0 || (c == 0 && !(a.hi.open && b.lo.open))
+}
+
+func union(a, b []interval) []interval {
+	all := append(append([]interval{}, a...), b...)
+	sort.Slice(all, func(i, j int) bool { return lowerBefore(all[i].lo, all[j].lo) })
+	var out []interval
+	for _, iv := range all {
+		if empty(iv) {
+			continue
+		}
+		if n := len(out); n > 0 && joins(out[n-1], iv) {
+			if upperBefore(out[n-1].hi, iv.hi) {
+				out[n-1].hi = iv.hi
+			}
+			continue
+		}
+		out = append(out, iv)
+	}
+	return out
+}
+
+func intersect(a, b []interval) []interval {
+	var out []interval
+	for _, x := range a {
+		for _, y := range b {
+			iv := x
+			if lowerBefore(iv.lo, y.lo) {
+				iv.lo = y.lo
+			}
+			if upperBefore(y.hi, iv.hi) {
+				iv.hi = y.hi
+			}
+			if !empty(iv) {
+				out = append(out, iv)
+			}
+		}
+	}
+	return union(out, nil)
+}
+
+// complement returns the values of the domain outside the sorted, disjoint intervals
+func complement(ranges []interval) []interval {
+	var out []interval
+	lo := bound{inf: true}
+	for _, iv := range ranges {
+		if !iv.lo.inf {
+			gap := interval{lo: lo, hi: bound{value: iv.lo.value, open: !iv.lo.open}}
+			if !empty(gap) {
+				out = append(out, gap)
+			}
+		}
+		if iv.hi.inf {
+			return out
+		}
+		lo = bound{value: iv.hi.value, open: !iv.hi.open}
+	}
+	return append(out, interval{lo: lo, hi: bound{inf: true}})
+}
@@ -0,0 +1,233 @@
+package logicflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+)
+
+// Compiled patterns of the matches comparison, shared by every run
+var patterns sync.Map
+
+// condition evaluates a condition. A group combines its conditions with AND, OR or NOT, NOT
+// holding when none of them does. A leaf compares the value at its variable key, and NOT negates
+// the comparison; on a key with [*] the arrays are quantified as quantify describes, and NOT
+// negates the outcome. A condition without a variable or conditions holds.
+func (r *run) condition(c models.Condition) (bool, error) {
+	if len(c.Conditions) > 0 {
+		operator := strings.ToUpper(c.Operator)
+		for _, sub := range c.Conditions {
+			matched, err := r.condition(sub)
+			if err != nil {
+				return false, err
+			}
+			switch operator {
+			case "AND":
+				if !matched {
+					return false, nil
+				}
+			case "OR":
+				if matched {
+					return true, nil
+				}
+			case "NOT":
+				if matched {
+					return false, nil
+				}
+			default:
+				return false, fmt.Errorf("unknown condition group operator %q", c.Operator)
+			}
+		}
+		return operator != "OR", nil
+	}
+	if c.Variable.VarKey == "" {
+		return true, nil
+	}
+	parts := segments(c.Variable.VarKey)
+	quantifiers, err := levelQuantifiers(c, parts[:len(parts)-1])
+	if err != nil {
+		return false, err
+	}
+	var matched bool
+	if len(parts) > 1 {
+		if matched, err = r.quantify(c, quantifiers, parts, path{}, 0); err != nil {
+			return false, err
+		}
+	} else {
+		actual, found := get(r.data, path{}.keys(parts[0]))
+		if matched, err = r.compare(actual, found, c.Logical, c.OpValue); err != nil {
+			return false, err
+		}
+		r.tracef("%s = %s", path{}.keys(parts[0]), text(actual))
+	}
+	if strings.EqualFold(c.Operator, "NOT") {
+		matched = !matched
+	}
+	r.tracef("%s%s %s %s: %t", describe(quantifiers, c.Operator, parts), c.Variable.VarKey, c.Logical, text(normalize(c.OpValue)), matched)
+	return matched, nil
+}
+
+// compare applies a comparison of conditions, array filters and count quantifiers to the actual
+// value. Numeric comparisons never hold for values that are not numbers, nor date comparisons for
+// values that are not dates or RFC 3339 timestamps; olderthandays and withindays count the days
+// back from the evaluation time.
+func (r *run) compare(actual interface{}, found bool, logical string, expected interface{}) (bool, error) {
+	expected = normalize(expected)
+	switch strings.ToLower(logical) {
+	case "empty":
+		return isEmpty(actual, found), nil
+	case "equal", "eq":
+		return found && text(actual) == text(expected), nil
+	case "notequal", "neq":
+		return !found || text(actual) != text(expected), nil
+	case "gt", "lt", "gte", "lte":
+		a, okA := toFloat(actual)
+		e, okE := toFloat(expected)
+		if !found || !okA || !okE {
+			return false, nil
+		}
+		switch strings.ToLower(logical) {
+		case "gt":
+			return a > e, nil
+		case "lt":
+			return a < e, nil
+		case "gte":
+			return a >= e, nil
+		default:
+			return a <= e, nil
+		}
+	case "before", "after":
+		a, okA := parseDate(actual)
+		e, okE := parseDate(expected)
+		if !found || !okA || !okE {
+			return false, nil
+		}
+		if strings.ToLower(logical) == "before" {
+			return a.Before(e), nil
+		}
+		return a.After(e), nil
+	case "olderthandays", "withindays":
+		a, okA := parseDate(actual)
+		days, okD := toFloat(expected)
+		if !found || !okA || !okD {
+			return false, nil
+		}
+		age := r.at.Sub(a).Hours() / 24
+		if strings.ToLower(logical) == "olderthandays" {
+			return age > days, nil
+		}
+		return age >= 0 && age <= days, nil
+	case "contains":
+		return found && strings.Contains(text(actual), text(expected)), nil
+	case "startswith":
+		return found && strings.HasPrefix(text(actual), text(expected)), nil
+	case "endswith":
+		return found && strings.HasSuffix(text(actual), text(expected)), nil
+	case "matches":
+		re, err := pattern(text(expected))
+		if err != nil {
+			return false, err
+		}
+		return found && re.MatchString(text(actual)), nil
+	case "inarray", "notinarray":
+		in := false
+		if list, ok := expected.([]interface{}); ok {
+			for _, item := range list {
+				if found && text(item) == text(actual) {
+					in = true
+					break
+				}
+			}
+		}
+		return in == (strings.ToLower(logical) == "inarray"), nil
+	default:
+		return false, fmt.Errorf("unknown comparison %q", logical)
+	}
+}
+
+func pattern(expr string) (*regexp.Regexp, error) {
+	if cached, ok := patterns.Load(expr); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", expr, err)
+	}
+	patterns.Store(expr, re)
+	return re, nil
+}
+
+// parseDate reads a date or an RFC 3339 timestamp
+func parseDate(v interface{}) (time.Time, bool) {
+	raw, ok := v.(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func isEmpty(v interface{}, found bool) bool {
+	if !found || v == nil {
+		return true
+	}
+	switch t := v.(type) {
+	case string:
+		return t == "" || t == "null" || t == "undefined"
+	case []interface{}:
+		return len(t) == 0
+	case map[string]interface{}:
+		return len(t) == 0
+	}
+	return false
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// text renders a value for the text comparisons and the trace
+func text(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case bool:
+		return strconv.FormatBool(t)
+	}
+	if f, ok := toFloat(v); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(raw)
+}
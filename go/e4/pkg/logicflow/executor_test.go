@@ -0,0 +1,314 @@
+package logicflow
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+)
+
+// executorCase runs flow on facts and expects the given values afterwards, by variable key, or an
+// error when wantErr is set.
+type executorCase struct {
+	name    string
+	facts   string
+	flow    string
+	want    map[string]interface{}
+	wantErr bool
+}
+
+var executorCases = []executorCase{
+	{
+		name:  "step without condition takes the true branch",
+		facts: `{"amount": 10}`,
+		flow: `[{"operation_name": "init",
+			"operation_if_true": [{"variable": {"var_key": "data.status"}, "operation": "SET", "op_value": "new"}],
+			"operation_if_false": [{"variable": {"var_key": "data.status"}, "operation": "SET", "op_value": "wrong"}]}]`,
+		want: map[string]interface{}{"status": "new"},
+	},
+	{
+		name:  "false branch on a failed condition",
+		facts: `{"amount": 10}`,
+		flow: `[{"condition": {"variable": {"var_key": "data.amount"}, "logical": "gt", "op_value": 100},
+			"operation_if_true": [{"variable": {"var_key": "data.band"}, "operation": "SET", "op_value": "high"}],
+			"operation_if_false": [{"variable": {"var_key": "data.band"}, "operation": "SET", "op_value": "low"}]}]`,
+		want: map[string]interface{}{"band": "low"},
+	},
+	{
+		name:  "nested AND of OR and NOT groups",
+		facts: `{"age": 30, "country": "IN", "tier": "gold", "blocked": false}`,
+		flow: `[{"condition": {"operator": "AND", "conditions": [
+				{"variable": {"var_key": "data.age"}, "logical": "gte", "op_value": 18},
+				{"operator": "OR", "conditions": [
+					{"variable": {"var_key": "data.country"}, "logical": "eq", "op_value": "US"},
+					{"variable": {"var_key": "data.tier"}, "logical": "inarray", "op_value": ["gold", "platinum"]}]},
+				{"operator": "NOT", "conditions": [
+					{"variable": {"var_key": "data.blocked"}, "logical": "eq", "op_value": true}]}]},
+			"operation_if_true": [{"variable": {"var_key": "data.eligible"}, "operation": "SET", "op_value": true}],
+			"operation_if_false": [{"variable": {"var_key": "data.eligible"}, "operation": "SET", "op_value": false}]}]`,
+		want: map[string]interface{}{"eligible": true},
+	},
+	{
+		name:  "nested group that fails deep down",
+		facts: `{"age": 30, "country": "IN", "tier": "silver"}`,
+		flow: `[{"condition": {"operator": "AND", "conditions": [
+				{"variable": {"var_key": "data.age"}, "logical": "gte", "op_value": 18},
+				{"operator": "OR", "conditions": [
+					{"variable": {"var_key": "data.country"}, "logical": "eq", "op_value": "US"},
+					{"variable": {"var_key": "data.tier"}, "logical": "eq", "op_value": "gold"}]}]},
+			"operation_if_true": [{"variable": {"var_key": "data.eligible"}, "operation": "SET", "op_value": true}],
+			"operation_if_false": [{"variable": {"var_key": "data.eligible"}, "operation": "SET", "op_value": false}]}]`,
+		want: map[string]interface{}{"eligible": false},
+	},
+	{
+		name:  "operation on filtered array elements",
+		facts: `{"orders": [{"status": "open", "total": 100}, {"status": "closed", "total": 100}]}`,
+		flow: `[{"operation_if_true": [{"variable": {"var_key": "data.orders[*].total"}, "operation": "MULT", "op_value": 2,
+				"array_filters": [{"array_name": "orders", "property": "status", "logical": "eq", "op_value": "open"}]},
+			{"variable": {"var_key": "data.orders[*].flag"}, "operation": "SET", "op_value": "seen"}]}]`,
+		want: map[string]interface{}{"orders": []interface{}{
+			map[string]interface{}{"status": "open", "total": 200.0, "flag": "seen"},
+			map[string]interface{}{"status": "closed", "total": 100.0, "flag": "seen"}}},
+	},
+	{
+		name:  "removing filtered elements",
+		facts: `{"items": [{"qty": 0}, {"qty": 2}, {"qty": 0}, {"qty": 3}]}`,
+		flow: `[{"operation_if_true": [{"variable": {"var_key": "data.items[*]"}, "operation": "DELETE",
+				"array_filters": [{"array_name": "items", "property": "qty", "logical": "lte", "op_value": 0}]}]}]`,
+		want: map[string]interface{}{"items": []interface{}{map[string]interface{}{"qty": 2.0}, map[string]interface{}{"qty": 3.0}}},
+	},
+	{
+		name:  "collecting from nested arrays",
+		facts: `{"orders": [{"items": [{"price": 2}, {"price": 3}]}, {"items": [{"price": 5}]}]}`,
+		flow: `[{"operation_if_true": [{"variable": {"var_key": "data.orders[*].items[*].price"}, "operation": "MULT", "op_value": 2}]},
+			{"operation_if_true": [{"variable": {"var_key": "data.summary.count"}, "operation": "COLLECT_COUNT"},
+				{"variable": {"var_key": "data.summary.first"}, "operation": "SET", "op_value": "data.orders", "value_is_path": true}]}]`,
+		want: map[string]interface{}{
+			"summary.count": 1.0,
+			"summary.first": []interface{}{
+				map[string]interface{}{"items": []interface{}{map[string]interface{}{"price": 4.0}, map[string]interface{}{"price": 6.0}}},
+				map[string]interface{}{"items": []interface{}{map[string]interface{}{"price": 10.0}}}}},
+	},
+	{
+		name:  "text, list and toggle operations",
+		facts: `{"name": "  Ada ", "tags": ["b", 10, "a", 2], "on": true, "log": [1, 2], "note": ""}`,
+		flow: `[{"condition": {"operator": "AND", "conditions": [
+				{"variable": {"var_key": "data.name"}, "logical": "matches", "op_value": "^\\s*A"},
+				{"variable": {"var_key": "data.note"}, "logical": "empty"},
+				{"variable": {"var_key": "data.name"}, "logical": "notinarray", "op_value": ["Bob"]}]},
+			"operation_if_true": [
+				{"variable": {"var_key": "data.name"}, "operation": "TRIM"},
+				{"variable": {"var_key": "data.name"}, "operation": "UPPERCASE"},
+				{"variable": {"var_key": "data.name"}, "operation": "APPEND", "op_value": "!"},
+				{"variable": {"var_key": "data.tags"}, "operation": "SORT_DESC"},
+				{"variable": {"var_key": "data.on"}, "operation": "TOGGLE"},
+				{"variable": {"var_key": "data.log"}, "operation": "REVERSE"},
+				{"variable": {"var_key": "data.log"}, "operation": "PUSH", "op_value": "{\"at\": 3}"},
+				{"variable": {"var_key": "data.extra.list"}, "operation": "COLLECT", "op_value": "7"},
+				{"variable": {"var_key": "data.note"}, "operation": "CLEAR"}]}]`,
+		want: map[string]interface{}{
+			"name":       "ADA!",
+			"tags":       []interface{}{"b", "a", 10.0, 2.0},
+			"on":         false,
+			"log":        []interface{}{2.0, 1.0, map[string]interface{}{"at": 3.0}},
+			"extra.list": []interface{}{7.0},
+			"note":       nil,
+		},
+	},
+	{
+		name:  "date comparisons",
+		facts: `{"issued": "2001-02-03", "matures": "2999-12-31T00:00:00+02:00"}`,
+		flow: `[{"condition": {"operator": "AND", "conditions": [
+				{"variable": {"var_key": "data.issued"}, "logical": "before", "op_value": "2001-02-03T00:00:01Z"},
+				{"variable": {"var_key": "data.matures"}, "logical": "after", "op_value": "2999-12-30"},
+				{"variable": {"var_key": "data.issued"}, "logical": "withindays", "op_value": 36500000},
+				{"operator": "NOT", "variable": {"var_key": "data.matures"}, "logical": "olderthandays", "op_value": 0}]},
+			"operation_if_true": [{"variable": {"var_key": "data.dated"}, "operation": "SET", "op_value": true}],
+			"operation_if_false": [{"variable": {"var_key": "data.dated"}, "operation": "SET", "op_value": false}]}]`,
+		want: map[string]interface{}{"dated": true},
+	},
+	{
+		name:  "text comparisons",
+		facts: `{"name": "  Ada ", "note": "", "code": "AB-12", "n": "5", "tags": {"a": 1}}`,
+		flow: `[{"condition": {"operator": "AND", "conditions": [
+				{"variable": {"var_key": "data.name"}, "logical": "matches", "op_value": "^\\s*A"},
+				{"variable": {"var_key": "data.note"}, "logical": "empty"},
+				{"variable": {"var_key": "data.missing.deep"}, "logical": "empty"},
+				{"variable": {"var_key": "data.name"}, "logical": "notinarray", "op_value": ["Bob"]},
+				{"variable": {"var_key": "data.code"}, "logical": "contains", "op_value": "B-"},
+				{"variable": {"var_key": "data.code"}, "logical": "startswith", "op_value": "AB"},
+				{"variable": {"var_key": "data.code"}, "logical": "endswith", "op_value": "12"},
+				{"variable": {"var_key": "data.n"}, "logical": "eq", "op_value": 5},
+				{"variable": {"var_key": "data.n"}, "logical": "gt", "op_value": "4.5"},
+				{"variable": {"var_key": "data.missing"}, "logical": "neq", "op_value": 5},
+				{"variable": {"var_key": "data.tags"}, "logical": "eq", "op_value": "{\"a\":1}"}]},
+			"operation_if_true": [{"variable": {"var_key": "data.matched"}, "operation": "SET", "op_value": true}],
+			"operation_if_false": [{"variable": {"var_key": "data.matched"}, "operation": "SET", "op_value": false}]}]`,
+		want: map[string]interface{}{"matched": true},
+	},
+	{
+		name:  "operations that create their parents",
+		facts: `{"profile": "none", "log": [1], "limits": {"max": 1}, "totals": 3}`,
+		flow: `[{"operation_if_true": [
+				{"variable": {"var_key": "data.profile.tier"}, "operation": "SET", "op_value": "it's gold"},
+				{"variable": {"var_key": "data.rules"}, "operation": "SET_OBJ", "op_value": "{\"max\": 5}"},
+				{"variable": {"var_key": "data.tmp.scratch"}, "operation": "CREATE_TEMP_OBJ"},
+				{"variable": {"var_key": "data.limits"}, "operation": "CREATE_TEMP_OBJ"},
+				{"variable": {"var_key": "data.log"}, "operation": "PUSH", "op_value": "{\"at\": 2}"},
+				{"variable": {"var_key": "data.events.list"}, "operation": "PUSH", "op_value": "[\"a\"]"},
+				{"variable": {"var_key": "data.totals"}, "operation": "COLLECT", "op_value": "7"},
+				{"variable": {"var_key": "data.totals"}, "operation": "COLLECT", "op_value": "x"}]}]`,
+		want: map[string]interface{}{
+			"profile":     map[string]interface{}{"tier": "it's gold"},
+			"rules":       map[string]interface{}{"max": 5.0},
+			"tmp.scratch": map[string]interface{}{},
+			"limits":      map[string]interface{}{"max": 1.0},
+			"log":         []interface{}{1.0, map[string]interface{}{"at": 2.0}},
+			"events.list": []interface{}{[]interface{}{"a"}},
+			"totals":      []interface{}{7.0, "x"},
+		},
+	},
+	{
+		name:  "operations on value paths",
+		facts: `{"price": "12.5", "qty": 2, "label": "Net", "list": [3, 1, "b", 2], "cart": {"items": [1]}, "old": 1}`,
+		flow: `[{"operation_if_true": [
+				{"variable": {"var_key": "data.total"}, "operation": "SET", "op_value": "data.price", "value_is_path": true},
+				{"variable": {"var_key": "data.total"}, "operation": "MULT", "op_value": "data.qty", "value_is_path": true},
+				{"variable": {"var_key": "data.total"}, "operation": "SUB", "op_value": 5},
+				{"variable": {"var_key": "data.count"}, "operation": "INCREMENT"},
+				{"variable": {"var_key": "data.left"}, "operation": "DECREMENT"},
+				{"variable": {"var_key": "data.sum"}, "operation": "COLLECT_SUM", "op_value": "data.qty", "value_is_path": true},
+				{"variable": {"var_key": "data.label"}, "operation": "PREPEND", "op_value": "Gross "},
+				{"variable": {"var_key": "data.label"}, "operation": "LOWERCASE"},
+				{"variable": {"var_key": "data.list"}, "operation": "SORT_ASC"},
+				{"variable": {"var_key": "data.copy"}, "operation": "SET", "op_value": "data.cart", "value_is_path": true},
+				{"variable": {"var_key": "data.cart.items"}, "operation": "PUSH", "op_value": "data.qty", "value_is_path": true},
+				{"variable": {"var_key": "data.gone"}, "operation": "SET", "op_value": "data.nothing.deep", "value_is_path": true},
+				{"variable": {"var_key": "data.old"}, "operation": "REMOVE"}]}]`,
+		want: map[string]interface{}{
+			"total":      20.0,
+			"count":      1.0,
+			"left":       -1.0,
+			"sum":        2.0,
+			"label":      "gross net",
+			"list":       []interface{}{1.0, 2.0, 3.0, "b"},
+			"copy":       map[string]interface{}{"items": []interface{}{1.0}},
+			"cart.items": []interface{}{1.0, 2.0},
+			"gone":       nil,
+			"old":        nil,
+		},
+	},
+	{
+		name:  "operations on array elements themselves",
+		facts: `{"rows": [{"a": 1}, "x", [1, 2], null, {"a": 2}], "lists": [[3, 1], [2]], "bins": [[1], 2], "temps": [1, {"k": 1}]}`,
+		flow: `[{"operation_if_true": [
+				{"variable": {"var_key": "data.lists[*]"}, "operation": "SORT_ASC"},
+				{"variable": {"var_key": "data.rows[*]"}, "operation": "SET", "op_value": "y",
+					"array_filters": [{"array_name": "rows", "property": "a", "logical": "eq", "op_value": 2}]},
+				{"variable": {"var_key": "data.rows[*]"}, "operation": "CLEAR",
+					"array_filters": [{"array_name": "rows", "property": "a", "logical": "eq", "op_value": 1}]},
+				{"variable": {"var_key": "data.bins[*]"}, "operation": "CLEAR"},
+				{"variable": {"var_key": "data.temps[*]"}, "operation": "CREATE_TEMP_OBJ"}]}]`,
+		want: map[string]interface{}{
+			"lists": []interface{}{[]interface{}{1.0, 3.0}, []interface{}{2.0}},
+			"rows":  []interface{}{"x", []interface{}{1.0, 2.0}, nil, "y"},
+			"bins":  []interface{}{[]interface{}{}},
+			"temps": []interface{}{map[string]interface{}{}, map[string]interface{}{"k": 1.0}},
+		},
+	},
+	{
+		name:  "condition is evaluated once, before the operations",
+		facts: `{"stage": "new"}`,
+		flow: `[{"condition": {"variable": {"var_key": "data.stage"}, "logical": "eq", "op_value": "new"},
+			"operation_if_true": [{"variable": {"var_key": "data.stage"}, "operation": "SET", "op_value": "done"}],
+			"operation_if_false": [{"variable": {"var_key": "data.wrong"}, "operation": "SET", "op_value": true}]}]`,
+		want: map[string]interface{}{"stage": "done", "wrong": nil},
+	},
+	{
+		name:    "pushing onto text fails",
+		facts:   `{"a": "x"}`,
+		flow:    `[{"operation_if_true": [{"variable": {"var_key": "data.a"}, "operation": "PUSH", "op_value": "1"}]}]`,
+		wantErr: true,
+	},
+	{
+		name:    "arithmetic on text fails",
+		facts:   `{"a": "x"}`,
+		flow:    `[{"operation_if_true": [{"variable": {"var_key": "data.a"}, "operation": "ADD", "op_value": 1}]}]`,
+		wantErr: true,
+	},
+	{
+		name:    "summing text fails",
+		facts:   `{}`,
+		flow:    `[{"operation_if_true": [{"variable": {"var_key": "data.sum"}, "operation": "COLLECT_SUM", "op_value": "x"}]}]`,
+		wantErr: true,
+	},
+	{
+		name:    "value path through an array fails",
+		facts:   `{"rows": [{"a": 1}]}`,
+		flow:    `[{"operation_if_true": [{"variable": {"var_key": "data.b"}, "operation": "SET", "op_value": "data.rows[*].a", "value_is_path": true}]}]`,
+		wantErr: true,
+	},
+	{
+		name:    "setting a property of an element that is not an object fails",
+		facts:   `{"rows": [{"a": 1}, "x"]}`,
+		flow:    `[{"operation_if_true": [{"variable": {"var_key": "data.rows[*].a"}, "operation": "SET", "op_value": 2}]}]`,
+		wantErr: true,
+	},
+	{
+		name:    "unknown comparison fails",
+		facts:   `{"a": 1}`,
+		flow:    `[{"condition": {"variable": {"var_key": "data.a"}, "logical": "around", "op_value": 1}}]`,
+		wantErr: true,
+	},
+	{
+		name:    "unknown operation fails",
+		facts:   `{"a": 1}`,
+		flow:    `[{"operation_if_true": [{"variable": {"var_key": "data.a"}, "operation": "EXPLODE"}]}]`,
+		wantErr: true,
+	},
+}
+
+// TestExecute runs the executor on flows covering both branches, nested condition groups, array
+// filters, comparisons and operations
+func TestExecute(t *testing.T) {
+	for _, tc := range executorCases {
+		t.Run(tc.name, tc.run)
+	}
+}
+
+// run executes the flow of the case and checks the facts it leaves, or that it fails
+func (tc executorCase) run(t *testing.T) {
+	flow, facts := tc.parse(t)
+	result, err := Execute(context.Background(), flow, facts, true)
+	if tc.wantErr {
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range tc.want {
+		got, _ := get(result.Data, path{}.keys(keys(key)))
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s = %s, expected %s", key, text(got), text(want))
+		}
+	}
+}
+
+// parse decodes the flow and the facts of the case
+func (tc executorCase) parse(t *testing.T) (models.LogicFlow, map[string]interface{}) {
+	t.Helper()
+	flow := models.LogicFlow{Name: tc.name}
+	if err := json.Unmarshal([]byte(tc.flow), &flow.LogicalSteps); err != nil {
+		t.Fatalf("bad flow: %v", err)
+	}
+	var facts map[string]interface{}
+	if err := json.Unmarshal([]byte(tc.facts), &facts); err != nil {
+		t.Fatalf("bad facts: %v", err)
+	}
+	return flow, facts
+}
@@ -0,0 +1,95 @@
+// Package logicflow executes logic flows: ordered steps that each test a condition on a fact
+// document and apply the operations of the outcome, OperationIfTrue when the condition holds and
+// OperationIfFalse otherwise. Variable keys with [*] walk every element of an array, narrowed by
+// the array filters of the condition or operation. A condition on such a key holds according to the
+// quantifier of each array it walks: any, all, none or a count of matching elements.
+package logicflow
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+)
+
+// Root of every variable key, e.g. data.applicant.age
+const factsRoot = "data"
+
+// Result holds the facts after every step ran. Trace is only filled when asked for.
+type Result struct {
+	Data  map[string]interface{}
+	Steps []StepResult
+	Trace []string
+}
+
+// StepResult tells which branch a step took and how many operations it applied; an operation on
+// an array key counts once per element it reached
+type StepResult struct {
+	Name    string `json:"name"`
+	Matched bool   `json:"matched"`
+	Applied int    `json:"applied"`
+}
+
+type run struct {
+	data  map[string]interface{}
+	at    time.Time
+	trace *[]string
+}
+
+func (r *run) tracef(format string, args ...interface{}) {
+	if r.trace != nil {
+		*r.trace = append(*r.trace, fmt.Sprintf(format, args...))
+	}
+}
+
+// Execute runs the flow as of now
+func Execute(ctx context.Context, flow models.LogicFlow, facts map[string]interface{}, trace bool) (*Result, error) {
+	return ExecuteAt(ctx, flow, facts, time.Now(), trace)
+}
+
+// ExecuteAt runs the steps of the flow in order on the facts, which it updates in place, with
+// relative date comparisons counting back from at. A step with an empty condition always takes
+// OperationIfTrue. It fails on an unknown comparison, quantifier or operation, on an operation
+// that does not fit the value it targets, and when ctx is done; the result then holds the steps run
+// so far.
+func ExecuteAt(ctx context.Context, flow models.LogicFlow, facts map[string]interface{}, at time.Time, trace bool) (*Result, error) {
+	if facts == nil {
+		facts = map[string]interface{}{}
+	}
+	result := &Result{Data: facts, Steps: []StepResult{}}
+	r := &run{data: facts, at: at}
+	if trace {
+		result.Trace = []string{}
+		r.trace = &result.Trace
+	}
+	for i, step := range flow.LogicalSteps {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		name := step.OperationName
+		if name == "" {
+			name = fmt.Sprintf("step %d", i+1)
+		}
+		matched, err := r.condition(step.Condition)
+		if err != nil {
+			return result, fmt.Errorf("%s: %w", name, err)
+		}
+		operations := step.OperationIfFalse
+		if matched {
+			operations = step.OperationIfTrue
+		}
+		r.tracef("%s: condition %t, applying %d operations", name, matched, len(operations))
+		stepResult := StepResult{Name: name, Matched: matched}
+		for _, op := range operations {
+			applied, err := r.operation(op)
+			stepResult.Applied += applied
+			if err != nil {
+				result.Steps = append(result.Steps, stepResult)
+				return result, fmt.Errorf("%s: %w", name, err)
+			}
+		}
+		result.Steps = append(result.Steps, stepResult)
+	}
+	return result, nil
+}
@@ -0,0 +1,202 @@
+package logicflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+)
+
+// operation applies an operation at every path its variable key designates and returns how many
+// it reached. Elements are updated last to first, so that removing one leaves the paths of the
+// others valid.
+func (r *run) operation(op models.Operation) (int, error) {
+	paths, err := r.expand(op.Variable.VarKey, op.ArrayFilters)
+	if err != nil {
+		return 0, err
+	}
+	value := normalize(op.OpValue)
+	if op.ValueIsPath {
+		source, _ := value.(string)
+		if strings.Contains(source, "[*]") {
+			return 0, fmt.Errorf("%s: value path %s cannot walk an array", op.Operation, source)
+		}
+		value, _ = get(r.data, path{}.keys(keys(strings.TrimPrefix(source, factsRoot+"."))))
+		// A copy, so that later operations on either side do not change the other
+		value = normalize(value)
+	}
+	for i := len(paths) - 1; i >= 0; i-- {
+		if err := r.apply(op.Operation, paths[i], value); err != nil {
+			return len(paths) - 1 - i, fmt.Errorf("%s on %s: %w", op.Operation, paths[i], err)
+		}
+		if current, found := get(r.data, paths[i]); found {
+			r.tracef("%s %s: %s", op.Operation, paths[i], text(current))
+		} else {
+			r.tracef("%s %s: removed", op.Operation, paths[i])
+		}
+	}
+	return len(paths), nil
+}
+
+func (r *run) apply(operation string, p path, value interface{}) error {
+	current, found := get(r.data, p)
+	switch strings.ToUpper(operation) {
+	case "SET":
+		return set(r.data, p, value)
+	case "SET_OBJ":
+		return set(r.data, p, parseJSON(value))
+	case "CREATE_TEMP_OBJ":
+		if isObject(current) {
+			return nil
+		}
+		return set(r.data, p, map[string]interface{}{})
+	case "ADD", "SUB", "MULT":
+		a, err := number(current, found)
+		if err != nil {
+			return err
+		}
+		b, ok := toFloat(value)
+		if !ok {
+			return fmt.Errorf("%s is not a number", text(value))
+		}
+		switch strings.ToUpper(operation) {
+		case "ADD":
+			return set(r.data, p, a+b)
+		case "SUB":
+			return set(r.data, p, a-b)
+		default:
+			return set(r.data, p, a*b)
+		}
+	case "INCREMENT", "DECREMENT":
+		a, err := number(current, found)
+		if err != nil {
+			return err
+		}
+		if strings.ToUpper(operation) == "INCREMENT" {
+			return set(r.data, p, a+1)
+		}
+		return set(r.data, p, a-1)
+	case "COLLECT":
+		if f, ok := value.(string); ok {
+			if n, isNumber := toFloat(f); isNumber {
+				value = n
+			}
+		}
+		return set(r.data, p, append(list(current), value))
+	case "COLLECT_SUM":
+		a, err := number(current, found)
+		if err != nil {
+			return err
+		}
+		b, ok := toFloat(value)
+		if !ok {
+			return fmt.Errorf("cannot sum %s, it is not a number", text(value))
+		}
+		return set(r.data, p, a+b)
+	case "COLLECT_COUNT":
+		a, err := number(current, found)
+		if err != nil {
+			return err
+		}
+		return set(r.data, p, a+1)
+	case "PUSH":
+		if found && current != nil {
+			if _, ok := current.([]interface{}); !ok {
+				return fmt.Errorf("%s is not an array", text(current))
+			}
+		}
+		return set(r.data, p, append(list(current), parseJSON(value)))
+	case "DELETE", "REMOVE":
+		return remove(r.data, p)
+	case "CLEAR":
+		if _, ok := current.([]interface{}); ok {
+			return set(r.data, p, []interface{}{})
+		}
+		return remove(r.data, p)
+	case "UPPERCASE":
+		return set(r.data, p, strings.ToUpper(text(current)))
+	case "LOWERCASE":
+		return set(r.data, p, strings.ToLower(text(current)))
+	case "TRIM":
+		return set(r.data, p, strings.TrimSpace(text(current)))
+	case "APPEND":
+		return set(r.data, p, text(current)+text(value))
+	case "PREPEND":
+		return set(r.data, p, text(value)+text(current))
+	case "TOGGLE":
+		return set(r.data, p, current != true)
+	case "REVERSE":
+		elems, ok := current.([]interface{})
+		if !ok {
+			return nil
+		}
+		reversed := make([]interface{}, len(elems))
+		for i, elem := range elems {
+			reversed[len(elems)-1-i] = elem
+		}
+		return set(r.data, p, reversed)
+	case "SORT_ASC", "SORT_DESC":
+		elems, ok := current.([]interface{})
+		if !ok {
+			return nil
+		}
+		sorted := append([]interface{}{}, elems...)
+		descending := strings.ToUpper(operation) == "SORT_DESC"
+		sort.SliceStable(sorted, func(i, j int) bool {
+			if descending {
+				return less(sorted[j], sorted[i])
+			}
+			return less(sorted[i], sorted[j])
+		})
+		return set(r.data, p, sorted)
+	default:
+		return fmt.Errorf("unknown operation %q", operation)
+	}
+}
+
+// number reads the numeric value an arithmetic operation starts from; a missing value counts as 0
+func number(current interface{}, found bool) (float64, error) {
+	if !found || current == nil {
+		return 0, nil
+	}
+	f, ok := toFloat(current)
+	if !ok {
+		return 0, fmt.Errorf("%s is not a number", text(current))
+	}
+	return f, nil
+}
+
+// list returns a copy of the array value, empty when there is none
+func list(current interface{}) []interface{} {
+	elems, _ := current.([]interface{})
+	return append([]interface{}{}, elems...)
+}
+
+// parseJSON reads a string holding a JSON object or array, as the designer sends them
+func parseJSON(value interface{}) interface{} {
+	raw, ok := value.(string)
+	if trimmed := strings.TrimSpace(raw); !ok || trimmed == "" || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return value
+	}
+	var doc interface{}
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+		return value
+	}
+	return doc
+}
+
+// less orders numbers numerically and numbers before anything else, which is ordered as text
+func less(a, b interface{}) bool {
+	fa, okA := toFloat(a)
+	fb, okB := toFloat(b)
+	switch {
+	case okA && okB:
+		return fa < fb
+	case okA != okB:
+		return okA
+	default:
+		return text(a) < text(b)
+	}
+}
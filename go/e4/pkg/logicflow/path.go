@@ -0,0 +1,232 @@
+package logicflow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+)
+
+var errRoot = errors.New("the facts themselves cannot be set or removed")
+
+// path is a resolved location in the facts: object keys as strings and array indexes as ints
+type path []interface{}
+
+func (p path) with(parts ...interface{}) path {
+	out := make(path, 0, len(p)+len(parts))
+	return append(append(out, p...), parts...)
+}
+
+// segments splits a variable key at each [*]: every segment but the last names an array, and the
+// last one the value within each element, empty for the element itself
+func segments(varKey string) [][]string {
+	varKey = strings.TrimPrefix(varKey, factsRoot+".")
+	var out [][]string
+	for _, part := range strings.Split(varKey, "[*]") {
+		out = append(out, keys(part))
+	}
+	return out
+}
+
+func keys(dotted string) []string {
+	dotted = strings.Trim(dotted, ".")
+	if dotted == "" {
+		return nil
+	}
+	return strings.Split(dotted, ".")
+}
+
+func (p path) keys(names []string) path {
+	parts := make([]interface{}, len(names))
+	for i, name := range names {
+		parts[i] = name
+	}
+	return p.with(parts...)
+}
+
+// get reads the value at p
+func get(data map[string]interface{}, p path) (interface{}, bool) {
+	var current interface{} = data
+	for _, part := range p {
+		switch key := part.(type) {
+		case string:
+			obj, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if current, ok = obj[key]; !ok {
+				return nil, false
+			}
+		case int:
+			arr, ok := current.([]interface{})
+			if !ok || key >= len(arr) {
+				return nil, false
+			}
+			current = arr[key]
+		}
+	}
+	return current, true
+}
+
+// set writes value at p, creating the missing objects along the way. Array elements must exist.
+func set(data map[string]interface{}, p path, value interface{}) error {
+	if len(p) == 0 {
+		return errRoot
+	}
+	var current interface{} = data
+	for i, part := range p {
+		last := i == len(p)-1
+		switch key := part.(type) {
+		case string:
+			obj, ok := current.(map[string]interface{})
+			if !ok {
+				return notReachable(p[:i])
+			}
+			if last {
+				obj[key] = value
+				return nil
+			}
+			next := obj[key]
+			if _, isIndex := p[i+1].(int); isIndex {
+				if _, isArray := next.([]interface{}); !isArray {
+					return notReachable(p[:i+1])
+				}
+			} else if !isObject(next) {
+				next = map[string]interface{}{}
+				obj[key] = next
+			}
+			current = next
+		case int:
+			arr, ok := current.([]interface{})
+			if !ok || key >= len(arr) {
+				return notReachable(p[:i+1])
+			}
+			if last {
+				arr[key] = value
+				return nil
+			}
+			current = arr[key]
+		}
+	}
+	return nil
+}
+
+// remove deletes the key or array element at p; removing what does not exist is not an error
+func remove(data map[string]interface{}, p path) error {
+	if len(p) == 0 {
+		return errRoot
+	}
+	parent, ok := get(data, p[:len(p)-1])
+	if !ok {
+		return nil
+	}
+	switch key := p[len(p)-1].(type) {
+	case string:
+		if obj, ok := parent.(map[string]interface{}); ok {
+			delete(obj, key)
+		}
+	case int:
+		if arr, ok := parent.([]interface{}); ok && key < len(arr) {
+			kept := append(append([]interface{}{}, arr[:key]...), arr[key+1:]...)
+			return set(data, p[:len(p)-1], kept)
+		}
+	}
+	return nil
+}
+
+func notReachable(p path) error {
+	return fmt.Errorf("%s is not an object or an existing array element", p)
+}
+
+func isObject(v interface{}) bool {
+	_, ok := v.(map[string]interface{})
+	return ok
+}
+
+// expand resolves a variable key to the paths it designates: one for a plain key, one per array
+// element, of every array on the way, that passes the filters for a key with [*]
+func (r *run) expand(varKey string, filters []models.ArrayFilter) ([]path, error) {
+	parts := segments(varKey)
+	paths := []path{{}}
+	for level, names := range parts {
+		var next []path
+		for _, p := range paths {
+			p = p.keys(names)
+			if level == len(parts)-1 {
+				next = append(next, p)
+				continue
+			}
+			arr, _ := get(r.data, p)
+			elems, _ := arr.([]interface{})
+			for i := range elems {
+				passed, err := r.filters(filters, names, p.with(i))
+				if err != nil {
+					return nil, err
+				}
+				if passed {
+					next = append(next, p.with(i))
+				}
+			}
+		}
+		paths = next
+	}
+	return paths, nil
+}
+
+// filters reports whether the array element at p passes the filters that apply to its array
+func (r *run) filters(filters []models.ArrayFilter, array []string, p path) (bool, error) {
+	for _, f := range filters {
+		if !namesArray(f.ArrayName, array) {
+			continue
+		}
+		actual, found := get(r.data, p.keys(keys(f.Property)))
+		passed, err := r.compare(actual, found, f.Logical, f.OpValue)
+		if err != nil {
+			return false, err
+		}
+		if !passed {
+			r.tracef("%s skipped: %s %s %s is false", p, f.Property, f.Logical, text(normalize(f.OpValue)))
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// namesArray reports whether an array name of a filter or quantifier designates the array at the
+// given keys: an empty name designates every array, others the array by its keys or its last key
+func namesArray(name string, array []string) bool {
+	name = strings.TrimSuffix(strings.TrimPrefix(name, factsRoot+"."), "[*]")
+	return name == "" || name == strings.Join(array, ".") || len(array) > 0 && name == array[len(array)-1]
+}
+
+// String spells the path the way variable keys do, with the element indexes
+func (p path) String() string {
+	var b strings.Builder
+	b.WriteString(factsRoot)
+	for _, part := range p {
+		switch key := part.(type) {
+		case string:
+			b.WriteString("." + key)
+		case int:
+			b.WriteString("[" + strconv.Itoa(key) + "]")
+		}
+	}
+	return b.String()
+}
+
+// normalize copies a value through JSON, so that values decoded from the database behave like
+// those read from a request
+func normalize(value interface{}) interface{} {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var out interface{}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return value
+	}
+	return out
+}
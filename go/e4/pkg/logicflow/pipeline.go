@@ -0,0 +1,619 @@
+package logicflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+)
+
+// Names the generated script defines. Condition steps strip "<target>." from their statements, so
+// the flow's conditions all target pipelineScope, which nothing in the script refers to.
+const (
+	pipelineScope = "__lf"
+	matchedVar    = "__lf_matched"
+	valueVar      = "__lf_value"
+)
+
+// Operations apply knows
+var operations = map[string]bool{
+	"SET": true, "SET_OBJ": true, "CREATE_TEMP_OBJ": true, "ADD": true, "SUB": true, "MULT": true,
+	"INCREMENT": true, "DECREMENT": true, "COLLECT": true, "COLLECT_SUM": true, "COLLECT_COUNT": true,
+	"PUSH": true, "DELETE": true, "REMOVE": true, "CLEAR": true, "UPPERCASE": true, "LOWERCASE": true,
+	"TRIM": true, "APPEND": true, "PREPEND": true, "TOGGLE": true, "REVERSE": true, "SORT_ASC": true,
+	"SORT_DESC": true,
+}
+
+// Member names that need no brackets in a path such as data.applicant.age
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// Dates and RFC 3339 timestamps, the values parseDate reads
+const dateJS = `/^\d{4}-\d{2}-\d{2}(T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2}))?$/`
+
+// ToPipeline converts a logic flow into engine pipeline steps that change the facts as Execute
+// does. Each logical step becomes condition steps whose statements read the facts through data
+// paths, holding the step's operations as assignment, push_array and remove steps; a step with
+// both branches records its outcome first, so that the condition is evaluated once. Operations
+// on array elements loop over the arrays, and value paths are copied before the operation uses
+// them. Where Execute fails at run time, such as on arithmetic with text, the script throws.
+// ToPipeline fails on a comparison, group operator, operation or quantifier the executor does not
+// know. Relative date comparisons count back from the time the script runs.
+func ToPipeline(flow models.LogicFlow) ([]models.PipelineStep, error) {
+	var steps []models.PipelineStep
+	for i, step := range flow.LogicalSteps {
+		name := step.OperationName
+		if name == "" {
+			name = fmt.Sprintf("step %d", i+1)
+		}
+		statement, err := conditionJS(step.Condition)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		branches := [][]models.Operation{step.OperationIfTrue, step.OperationIfFalse}
+		applied := make([][]models.PipelineStep, len(branches))
+		for b, ops := range branches {
+			for _, op := range ops {
+				opSteps, err := operationSteps(op)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", name, err)
+				}
+				applied[b] = append(applied[b], opSteps...)
+			}
+		}
+		switch {
+		case statement == "true":
+			steps = append(steps, applied[0]...)
+		case len(applied[1]) == 0:
+			if len(applied[0]) > 0 {
+				steps = append(steps, conditionStep(statement, applied[0]...))
+			}
+		case len(applied[0]) == 0:
+			steps = append(steps, conditionStep("!("+statement+")", applied[1]...))
+		default:
+			steps = append(steps,
+				assignStep(matchedVar, "false"),
+				conditionStep(statement, assignStep(matchedVar, "true")),
+				conditionStep(matchedVar, applied[0]...),
+				conditionStep("!"+matchedVar, applied[1]...))
+		}
+	}
+	return []models.PipelineStep{conditionStep("true", steps...)}, nil
+}
+
+// conditionJS writes a condition as a JavaScript expression with the semantics of condition
+func conditionJS(c models.Condition) (string, error) {
+	if len(c.Conditions) > 0 {
+		var subs []string
+		for _, sub := range c.Conditions {
+			statement, err := conditionJS(sub)
+			if err != nil {
+				return "", err
+			}
+			subs = append(subs, "("+statement+")")
+		}
+		switch strings.ToUpper(c.Operator) {
+		case "AND":
+			return strings.Join(subs, " && "), nil
+		case "OR":
+			return strings.Join(subs, " || "), nil
+		case "NOT":
+			return "!(" + strings.Join(subs, " || ") + ")", nil
+		}
+		return "", fmt.Errorf("unknown condition group operator %q", c.Operator)
+	}
+	if c.Variable.VarKey == "" {
+		return "true", nil
+	}
+	parts := segments(c.Variable.VarKey)
+	quantifiers, err := levelQuantifiers(c, parts[:len(parts)-1])
+	if err != nil {
+		return "", err
+	}
+	statement, err := quantifyJS(c, quantifiers, parts, factsRoot, 0)
+	if err != nil {
+		return "", err
+	}
+	if strings.EqualFold(c.Operator, "NOT") {
+		statement = "!(" + statement + ")"
+	}
+	return statement, nil
+}
+
+// quantifyJS writes the leaf on the level of parts below base the way quantify evaluates it: the
+// comparison at the innermost level, and the level's quantifier over the elements of its array
+// that pass the array filters otherwise
+func quantifyJS(c models.Condition, quantifiers []models.ArrayQuantifier, parts [][]string, base string, level int) (string, error) {
+	if level == len(parts)-1 {
+		return leafJS(c.Logical, c.OpValue, base, parts[level])
+	}
+	array := pathOf(base, parts[level])
+	elem := "e" + strconv.Itoa(level)
+	considered := fmt.Sprintf("(Array.isArray(%[1]s) ? %[1]s : [])", array)
+	if guard := parentsJS(base, parts[level]); guard != "" {
+		considered = fmt.Sprintf("(%s || !Array.isArray(%s) ? [] : %s)", guard, array, array)
+	}
+	var filters []string
+	for _, f := range c.ArrayFilters {
+		if !namesArray(f.ArrayName, parts[level]) {
+			continue
+		}
+		filter, err := leafJS(f.Logical, f.OpValue, elem, keys(f.Property))
+		if err != nil {
+			return "", err
+		}
+		filters = append(filters, "("+filter+")")
+	}
+	if len(filters) > 0 {
+		considered += fmt.Sprintf(".filter(function (%s) { return %s; })", elem, strings.Join(filters, " && "))
+	}
+	inner, err := quantifyJS(c, quantifiers, parts, elem, level+1)
+	if err != nil {
+		return "", err
+	}
+	matches := fmt.Sprintf("function (%s) { return %s; }", elem, inner)
+	switch q := quantifiers[level]; q.Quantifier {
+	case quantifierAll:
+		return fmt.Sprintf("(function (a) { return a.length > 0 && a.every(%s); })(%s)", matches, considered), nil
+	case quantifierNone:
+		return fmt.Sprintf("!%s.some(%s)", considered, matches), nil
+	case quantifierCount:
+		count, err := compareJS(q.Logical, q.OpValue, "n")
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(function (n) { return %s; })(%s.filter(%s).length)", count, considered, matches), nil
+	default:
+		return fmt.Sprintf("%s.some(%s)", considered, matches), nil
+	}
+}
+
+// leafJS applies a comparison to the value at names below base. Where an object on the way is
+// missing the value is, and the comparison holds as it does for a missing value.
+func leafJS(logical string, expected interface{}, base string, names []string) (string, error) {
+	test, err := compareJS(logical, expected, pathOf(base, names))
+	if err != nil {
+		return "", err
+	}
+	guard := parentsJS(base, names)
+	if guard == "" {
+		return test, nil
+	}
+	if missing, _ := (&run{}).compare(nil, false, logical, expected); missing {
+		return guard + " || " + test, nil
+	}
+	return "!(" + guard + ") && " + test, nil
+}
+
+// parentsJS writes the test that an object on the way to names below base is missing, if any;
+// array elements may be null themselves
+func parentsJS(base string, names []string) string {
+	var missing []string
+	if base != factsRoot && len(names) > 0 {
+		missing = append(missing, base+" == null")
+	}
+	for i := 1; i < len(names); i++ {
+		missing = append(missing, pathOf(base, names[:i])+" == null")
+	}
+	return strings.Join(missing, " || ")
+}
+
+// compareJS writes the comparison compare applies to the value at v, undefined when missing
+func compareJS(logical string, expected interface{}, v string) (string, error) {
+	expected = normalize(expected)
+	isNumber := fmt.Sprintf(`(typeof %[1]s === "number" || typeof %[1]s === "string" && %[1]s.trim() !== "")`, v)
+	isDate := fmt.Sprintf(`typeof %[1]s === "string" && %[2]s.test(%[1]s)`, v, dateJS)
+	switch strings.ToLower(logical) {
+	case "empty":
+		return fmt.Sprintf(`(%[1]s == null || %[1]s === "" || %[1]s === "null" || %[1]s === "undefined" || typeof %[1]s === "object" && Object.keys(%[1]s).length === 0)`, v), nil
+	case "equal", "eq":
+		return equalJS(v, text(expected)), nil
+	case "notequal", "neq":
+		return "!(" + equalJS(v, text(expected)) + ")", nil
+	case "gt", "lt", "gte", "lte":
+		e, ok := toFloat(expected)
+		if !ok {
+			return "false", nil
+		}
+		operators := map[string]string{"gt": ">", "lt": "<", "gte": ">=", "lte": "<="}
+		return fmt.Sprintf("%s && Number(%s) %s %s", isNumber, v, operators[strings.ToLower(logical)], strconv.FormatFloat(e, 'g', -1, 64)), nil
+	case "before", "after":
+		e, ok := parseDate(expected)
+		if !ok {
+			return "false", nil
+		}
+		operator := "<"
+		if strings.ToLower(logical) == "after" {
+			operator = ">"
+		}
+		return fmt.Sprintf("%s && Date.parse(%s) %s %d", isDate, v, operator, e.UnixMilli()), nil
+	case "olderthandays", "withindays":
+		days, ok := toFloat(expected)
+		if !ok {
+			return "false", nil
+		}
+		age := fmt.Sprintf("(Date.now() - Date.parse(%s)) / 86400000", v)
+		if strings.ToLower(logical) == "olderthandays" {
+			return fmt.Sprintf("%s && %s > %s", isDate, age, strconv.FormatFloat(days, 'g', -1, 64)), nil
+		}
+		return fmt.Sprintf("%s && %s >= 0 && %s <= %s", isDate, age, age, strconv.FormatFloat(days, 'g', -1, 64)), nil
+	case "contains":
+		return fmt.Sprintf("%s !== undefined && %s.includes(%s)", v, textJS(v), jsJSON(text(expected))), nil
+	case "startswith":
+		return fmt.Sprintf("%s !== undefined && %s.startsWith(%s)", v, textJS(v), jsJSON(text(expected))), nil
+	case "endswith":
+		return fmt.Sprintf("%s !== undefined && %s.endsWith(%s)", v, textJS(v), jsJSON(text(expected))), nil
+	case "matches":
+		if _, err := pattern(text(expected)); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s !== undefined && new RegExp(%s).test(%s)", v, jsJSON(text(expected)), textJS(v)), nil
+	case "inarray", "notinarray":
+		var in []string
+		if list, ok := expected.([]interface{}); ok {
+			for _, item := range list {
+				in = append(in, equalJS(v, text(item)))
+			}
+		}
+		test := "false"
+		if len(in) > 0 {
+			test = "(" + strings.Join(in, " || ") + ")"
+		}
+		if strings.ToLower(logical) == "notinarray" {
+			return "!" + test, nil
+		}
+		return test, nil
+	default:
+		return "", fmt.Errorf("unknown comparison %q", logical)
+	}
+}
+
+// equalJS writes the test that the text of the value at v is e: the values whose text is e are
+// known up front, but for objects and arrays, which are compared as JSON
+func equalJS(v, e string) string {
+	switch {
+	case e == "":
+		return fmt.Sprintf(`(%[1]s === null || %[1]s === "")`, v)
+	case e == "true" || e == "false":
+		return fmt.Sprintf("(%[1]s === %[2]s || %[1]s === %[3]s)", v, e, jsJSON(e))
+	case strings.HasPrefix(e, "{") || strings.HasPrefix(e, "["):
+		return fmt.Sprintf("(%s !== undefined && %s === %s)", v, textJS(v), jsJSON(e))
+	}
+	if f, ok := toFloat(e); ok && text(f) == e {
+		return fmt.Sprintf("(%[1]s === %[2]s || %[1]s === %[3]s)", v, e, jsJSON(e))
+	}
+	return fmt.Sprintf("%s === %s", v, jsJSON(e))
+}
+
+// textJS writes the text of the value at v the way text renders it
+func textJS(v string) string {
+	return fmt.Sprintf(`(%[1]s === null ? "" : typeof %[1]s === "object" ? JSON.stringify(%[1]s) : String(%[1]s))`, v)
+}
+
+// operationSteps writes an operation as the steps that apply it at every path its variable key
+// designates. Operations on a key with [*] loop over the arrays with for_each steps, the elements
+// that fail the array filters skipped; those on the elements themselves assign the array mapped
+// or filtered instead. A value path is copied into valueVar first.
+func operationSteps(op models.Operation) ([]models.PipelineStep, error) {
+	operation := strings.ToUpper(op.Operation)
+	if !operations[operation] {
+		return nil, fmt.Errorf("unknown operation %q", op.Operation)
+	}
+	value := operand{js: jsJSON(normalize(op.OpValue)), literal: true, value: normalize(op.OpValue)}
+	var steps []models.PipelineStep
+	if op.ValueIsPath {
+		source, _ := value.value.(string)
+		if strings.Contains(source, "[*]") {
+			// Execute fails on such an operation whenever it runs
+			message := fmt.Sprintf("%s: value path %s cannot walk an array", op.Operation, source)
+			return []models.PipelineStep{expressionAssignStep(valueVar, throwJS(jsJSON(message)))}, nil
+		}
+		names := keys(strings.TrimPrefix(source, factsRoot+"."))
+		read := pathJS(names)
+		if missing := parentsJS(factsRoot, names); missing != "" {
+			read = fmt.Sprintf("(%s ? undefined : %s)", missing, read)
+		}
+		steps = append(steps, expressionAssignStep(valueVar,
+			fmt.Sprintf("(function (v) { return v === undefined ? null : JSON.parse(JSON.stringify(v)); })(%s)", read)))
+		value = operand{js: valueVar}
+	}
+	parts := segments(op.Variable.VarKey)
+	if len(parts) == 1 {
+		if len(parts[0]) == 0 {
+			return nil, fmt.Errorf("%s: %w", op.Operation, errRoot)
+		}
+		return append(steps, targetSteps(operation, value, factsRoot, parts[0])...), nil
+	}
+	elementSteps, err := arraySteps(operation, value, op.ArrayFilters, parts, factsRoot, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s on %s: %w", op.Operation, op.Variable.VarKey, err)
+	}
+	return append(steps, elementSteps...), nil
+}
+
+// arraySteps apply an operation to the elements of the array at the level of parts below base
+// that pass the filters of the array
+func arraySteps(operation string, value operand, filters []models.ArrayFilter, parts [][]string, base string, level int) ([]models.PipelineStep, error) {
+	array := pathOf(base, parts[level])
+	guard := "Array.isArray(" + array + ")"
+	if missing := parentsJS(base, parts[level]); missing != "" {
+		guard = "!(" + missing + ") && " + guard
+	}
+	elem := "e" + strconv.Itoa(level)
+	var passing []string
+	for _, f := range filters {
+		if !namesArray(f.ArrayName, parts[level]) {
+			continue
+		}
+		filter, err := leafJS(f.Logical, f.OpValue, elem, keys(f.Property))
+		if err != nil {
+			return nil, err
+		}
+		passing = append(passing, "("+filter+")")
+	}
+	passes := "true"
+	if len(passing) > 0 {
+		passes = strings.Join(passing, " && ")
+	}
+	last := level == len(parts)-2
+	if last && len(parts[level+1]) == 0 {
+		return []models.PipelineStep{conditionStep(guard, expressionAssignStep(array, elementsJS(operation, value, array, elem, passes)))}, nil
+	}
+	var body []models.PipelineStep
+	if last {
+		body = targetSteps(operation, value, elem, parts[level+1])
+	} else {
+		inner, err := arraySteps(operation, value, filters, parts, elem, level+1)
+		if err != nil {
+			return nil, err
+		}
+		body = inner
+	}
+	return []models.PipelineStep{conditionStep(guard, models.PipelineStep{
+		Type: "for_each", Target: array, ContextVar: elem, Children: []models.PipelineStep{conditionStep(passes, body...)},
+	})}, nil
+}
+
+// targetSteps apply an operation to the value at names below base, the facts or an array element
+func targetSteps(operation string, value operand, base string, names []string) []models.PipelineStep {
+	target := pathOf(base, names)
+	present := ""
+	if missing := parentsJS(base, names); missing != "" {
+		present = "!(" + missing + ") && "
+	}
+	switch operation {
+	case "DELETE", "REMOVE":
+		if present == "" {
+			return []models.PipelineStep{removeStep(target)}
+		}
+		return []models.PipelineStep{conditionStep(strings.TrimSuffix(present, " && "), removeStep(target))}
+	case "CLEAR":
+		return []models.PipelineStep{
+			conditionStep(present+"!Array.isArray("+target+")", removeStep(target)),
+			conditionStep(present+"Array.isArray("+target+")", assignStep(target, "[]")),
+		}
+	case "REVERSE", "SORT_ASC", "SORT_DESC":
+		return []models.PipelineStep{conditionStep(present+"Array.isArray("+target+")", expressionAssignStep(target, updateJS(operation, value, target)))}
+	}
+
+	// The other operations set the value, replacing what is not an object on the way with one;
+	// within an element that is not an object they fail, as set does
+	var steps []models.PipelineStep
+	if base != factsRoot {
+		steps = append(steps, conditionStep(notObjectJS(base),
+			expressionAssignStep(target, throwJS(jsJSON(fmt.Sprintf("%s: an array element is not an object", operation))))))
+	}
+	for i := 1; i < len(names); i++ {
+		parent := pathOf(base, names[:i])
+		steps = append(steps, conditionStep(notObjectJS(parent), assignStep(parent, "{}")))
+	}
+	switch {
+	case operation == "CREATE_TEMP_OBJ":
+		return append(steps, conditionStep(notObjectJS(target), assignStep(target, "{}")))
+	case operation == "SET" && value.literal:
+		return append(steps, assignStep(target, value.js))
+	case operation == "SET_OBJ" && value.literal:
+		return append(steps, assignStep(target, jsJSON(parseJSON(value.value))))
+	case operation == "PUSH" && value.literal:
+		// Pushing onto anything else but an array fails, as in apply
+		return append(steps, pushStep(target, parseJSON(value.value)))
+	case operation == "COLLECT" && value.literal:
+		return append(steps, conditionStep("!Array.isArray("+target+")", assignStep(target, "[]")), pushStep(target, collected(value.value)))
+	}
+	return append(steps, expressionAssignStep(target, updateJS(operation, value, target)))
+}
+
+// elementsJS writes the array at array with the operation applied to the elements that pass
+func elementsJS(operation string, value operand, array, elem, passes string) string {
+	switch operation {
+	case "DELETE", "REMOVE":
+		return fmt.Sprintf("%s.filter(function (%s) { return !(%s); })", array, elem, passes)
+	case "CLEAR":
+		return fmt.Sprintf("%[1]s.filter(function (%[2]s) { return Array.isArray(%[2]s) || !(%[3]s); }).map(function (%[2]s) { return Array.isArray(%[2]s) && (%[3]s) ? [] : %[2]s; })",
+			array, elem, passes)
+	case "CREATE_TEMP_OBJ":
+		return fmt.Sprintf("%[1]s.map(function (%[2]s) { return (%[3]s) && (%[4]s) ? {} : %[2]s; })", array, elem, passes, notObjectJS(elem))
+	case "REVERSE", "SORT_ASC", "SORT_DESC":
+		return fmt.Sprintf("%[1]s.map(function (%[2]s) { return (%[3]s) && Array.isArray(%[2]s) ? %[4]s : %[2]s; })", array, elem, passes, updateJS(operation, value, elem))
+	}
+	return fmt.Sprintf("%[1]s.map(function (%[2]s) { return %[3]s ? %[4]s : %[2]s; })", array, elem, passes, updateJS(operation, value, elem))
+}
+
+// updateJS writes the value the operation sets, given the current value at current. It fails the
+// way apply does on values that are not numbers, or not arrays for PUSH.
+func updateJS(operation string, value operand, current string) string {
+	switch operation {
+	case "SET":
+		return value.js
+	case "SET_OBJ":
+		return value.parsedJS()
+	case "CREATE_TEMP_OBJ":
+		return "{}"
+	case "ADD", "SUB", "MULT":
+		operators := map[string]string{"ADD": "+", "SUB": "-", "MULT": "*"}
+		return fmt.Sprintf("%s %s %s", numberJS(current), operators[operation], value.numberJS(`%s + " is not a number"`))
+	case "INCREMENT", "COLLECT_COUNT":
+		return numberJS(current) + " + 1"
+	case "DECREMENT":
+		return numberJS(current) + " - 1"
+	case "COLLECT_SUM":
+		return numberJS(current) + " + " + value.numberJS(`"cannot sum " + %s + ", it is not a number"`)
+	case "COLLECT":
+		return fmt.Sprintf("(Array.isArray(%[1]s) ? %[1]s : []).concat([%[2]s])", current, value.collectedJS())
+	case "PUSH":
+		return fmt.Sprintf("(%[1]s == null ? [] : Array.isArray(%[1]s) ? %[1]s : %[2]s).concat([%[3]s])",
+			current, throwJS(textJS(current)+` + " is not an array"`), value.parsedJS())
+	case "UPPERCASE":
+		return textJS(current) + ".toUpperCase()"
+	case "LOWERCASE":
+		return textJS(current) + ".toLowerCase()"
+	case "TRIM":
+		return textJS(current) + ".trim()"
+	case "APPEND":
+		return textJS(current) + " + " + value.textJS()
+	case "PREPEND":
+		return value.textJS() + " + " + textJS(current)
+	case "TOGGLE":
+		return current + " !== true"
+	case "REVERSE":
+		return current + ".slice().reverse()"
+	case "SORT_ASC", "SORT_DESC":
+		return sortJS(current, operation == "SORT_DESC")
+	}
+	return "undefined"
+}
+
+// numberJS writes the number an arithmetic operation starts from, 0 for a missing value, as number
+func numberJS(v string) string {
+	return fmt.Sprintf(`(%[1]s == null ? 0 : %[2]s ? Number(%[1]s) : %[3]s)`,
+		v, isNumberJS(v), throwJS(textJS(v)+` + " is not a number"`))
+}
+
+// isNumberJS writes the test that the value at v is a number or a string holding one, as toFloat reads them
+func isNumberJS(v string) string {
+	return fmt.Sprintf(`(typeof %[1]s === "number" || typeof %[1]s === "string" && %[1]s.trim() !== "" && !isNaN(%[1]s))`, v)
+}
+
+// sortJS writes the array at v sorted the way less orders values, keeping equal ones in order
+func sortJS(v string, descending bool) string {
+	a, b := "x[0]", "y[0]"
+	if descending {
+		a, b = b, a
+	}
+	return fmt.Sprintf(`%s.map(function (v, i) { return [v, i]; }).sort(function (x, y) { `+
+		`var a = %s, b = %s, na = %s, nb = %s; `+
+		`var c = na && nb ? Number(a) - Number(b) : na !== nb ? (na ? -1 : 1) : %s < %s ? -1 : %s > %s ? 1 : 0; `+
+		`return c || x[1] - y[1]; }).map(function (p) { return p[0]; })`,
+		v, a, b, isNumberJS("a"), isNumberJS("b"), textJS("a"), textJS("b"), textJS("a"), textJS("b"))
+}
+
+// operand is the value of an operation: a literal, or valueVar holding the copy of a value path
+type operand struct {
+	js      string
+	literal bool
+	value   interface{}
+}
+
+// numberJS writes the operand as a number, failing with the message written from its text otherwise
+func (o operand) numberJS(message string) string {
+	if o.literal {
+		if f, ok := toFloat(o.value); ok {
+			return strconv.FormatFloat(f, 'g', -1, 64)
+		}
+		return throwJS(fmt.Sprintf(message, jsJSON(text(o.value))))
+	}
+	return fmt.Sprintf("(%s ? Number(%s) : %s)", isNumberJS(o.js), o.js, throwJS(fmt.Sprintf(message, textJS(o.js))))
+}
+
+func (o operand) textJS() string {
+	if o.literal {
+		return jsJSON(text(o.value))
+	}
+	return textJS(o.js)
+}
+
+// parsedJS writes the operand as parseJSON reads it
+func (o operand) parsedJS() string {
+	if o.literal {
+		return jsJSON(parseJSON(o.value))
+	}
+	return fmt.Sprintf(`(typeof %[1]s === "string" && /^\s*[\[{]/.test(%[1]s) ? (function (s) { try { return JSON.parse(s); } catch (e) { return s; } })(%[1]s) : %[1]s)`, o.js)
+}
+
+// collectedJS writes the operand as COLLECT appends it, strings holding numbers as numbers
+func (o operand) collectedJS() string {
+	if o.literal {
+		return jsJSON(collected(o.value))
+	}
+	return fmt.Sprintf(`(typeof %[1]s === "string" && %[2]s ? Number(%[1]s) : %[1]s)`, o.js, isNumberJS(o.js))
+}
+
+func collected(value interface{}) interface{} {
+	if s, ok := value.(string); ok {
+		if n, isNumber := toFloat(s); isNumber {
+			return n
+		}
+	}
+	return value
+}
+
+// pathJS writes the facts member at a path, such as data.applicant.age
+func pathJS(names []string) string {
+	return pathOf(factsRoot, names)
+}
+
+func pathOf(base string, names []string) string {
+	for _, name := range names {
+		if identifier.MatchString(name) {
+			base += "." + name
+		} else {
+			base += "[" + jsJSON(name) + "]"
+		}
+	}
+	return base
+}
+
+func notObjectJS(target string) string {
+	return fmt.Sprintf(`%[1]s == null || typeof %[1]s !== "object" || Array.isArray(%[1]s)`, target)
+}
+
+func conditionStep(statement string, children ...models.PipelineStep) models.PipelineStep {
+	return models.PipelineStep{Type: "condition", Target: pipelineScope, Statement: statement, Children: children}
+}
+
+func assignStep(target, value string) models.PipelineStep {
+	return models.PipelineStep{Type: "assignment", Target: target, Value: json.RawMessage(value)}
+}
+
+// expressionAssignStep assigns the value of an expression computed from the facts
+func expressionAssignStep(target, expr string) models.PipelineStep {
+	return models.PipelineStep{Type: "assignment", Target: target, Statement: expr}
+}
+
+func removeStep(target string) models.PipelineStep {
+	return models.PipelineStep{Type: "remove", Target: target}
+}
+
+func throwJS(message string) string {
+	return "(function () { throw new Error(" + message + "); })()"
+}
+
+// pushStep appends a value to the array at target; push_array values hold the JSON as a string
+func pushStep(target string, value interface{}) models.PipelineStep {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		raw = []byte("null")
+	}
+	return models.PipelineStep{Type: "push_array", Target: target, Value: json.RawMessage(jsJSON(string(raw)))}
+}
+
+// jsJSON encodes a value as JSON that can also be embedded in the script's single-quoted log messages
+func jsJSON(value interface{}) string {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return "null"
+	}
+	return strings.ReplaceAll(string(raw), "'", `\u0027`)
+}
@@ -0,0 +1,36 @@
+package logicflow
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/prithvirajv06/nimbus-uta/go/engine/engine"
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+)
+
+// TestPipeline converts the flows of TestExecute and TestQuantify into engine pipelines and runs
+// their scripts, which must leave the facts Execute leaves, or fail where it fails.
+func TestPipeline(t *testing.T) {
+	for _, tc := range append(append([]executorCase{}, executorCases...), quantifierCases...) {
+		t.Run(tc.name, func(t *testing.T) {
+			flow, executed := tc.parse(t)
+			_, scripted := tc.parse(t)
+			_, execErr := Execute(context.Background(), flow, executed, false)
+			pipeline, err := ToPipeline(flow)
+			if err == nil {
+				script := engine.GenerateScript(models.WorkflowDef{Engine: flow.Name, Pipeline: pipeline})
+				scripted, _, err = engine.ExecuteContext(context.Background(), script, scripted)
+			}
+			switch {
+			case execErr != nil && err != nil:
+			case execErr != nil:
+				t.Errorf("the pipeline succeeded where Execute failed: %v", execErr)
+			case err != nil:
+				t.Errorf("the pipeline failed: %v", err)
+			case !reflect.DeepEqual(normalize(executed), normalize(scripted)):
+				t.Errorf("the pipeline left %s, Execute %s", text(scripted), text(executed))
+			}
+		})
+	}
+}
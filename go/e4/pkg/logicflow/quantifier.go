@@ -0,0 +1,154 @@
+package logicflow
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prithvirajv06/nimbus-uta/go/engine/internal/models"
+)
+
+// Array quantifiers
+const (
+	quantifierAny   = "any"
+	quantifierAll   = "all"
+	quantifierNone  = "none"
+	quantifierCount = "count"
+)
+
+// Comparisons a count quantifier can apply to the number of matching elements
+var countComparisons = map[string]bool{"eq": true, "equal": true, "neq": true, "notequal": true, "gt": true, "gte": true, "lt": true, "lte": true}
+
+// levelQuantifiers picks the quantifier of each array of a variable key, outermost first. The first
+// of the condition's quantifiers whose array name designates an array applies to it, as for array
+// filters; the other arrays take all when the condition's operator is ALL or AND, else any. It
+// fails on an unknown quantifier, on a count without a numeric comparison and on a quantifier that
+// designates none of the arrays.
+func levelQuantifiers(c models.Condition, arrays [][]string) ([]models.ArrayQuantifier, error) {
+	fallback := quantifierAny
+	if operator := strings.ToUpper(c.Operator); operator == "ALL" || operator == "AND" {
+		fallback = quantifierAll
+	}
+	levels := make([]models.ArrayQuantifier, len(arrays))
+	explicit := make([]bool, len(arrays))
+	for i := range levels {
+		levels[i] = models.ArrayQuantifier{Quantifier: fallback}
+	}
+	for _, q := range c.Quantifiers {
+		q.Quantifier = strings.ToLower(q.Quantifier)
+		switch q.Quantifier {
+		case quantifierAny, quantifierAll, quantifierNone:
+		case quantifierCount:
+			if !countComparisons[strings.ToLower(q.Logical)] {
+				return nil, fmt.Errorf("count quantifier of %q needs a comparison: eq, neq, gt, gte, lt or lte", q.ArrayName)
+			}
+		default:
+			return nil, fmt.Errorf("unknown quantifier %q", q.Quantifier)
+		}
+		designated := false
+		for i, array := range arrays {
+			if !namesArray(q.ArrayName, array) {
+				continue
+			}
+			designated = true
+			if !explicit[i] {
+				levels[i], explicit[i] = q, true
+			}
+		}
+		if !designated {
+			return nil, fmt.Errorf("quantifier %s of %q designates no array of %s", q.Quantifier, q.ArrayName, c.Variable.VarKey)
+		}
+	}
+	return levels, nil
+}
+
+// quantify evaluates a leaf on an array key level by level, from the array of parts[level] in the
+// element at prefix. The elements considered at a level are those that pass the array filters of
+// their array. An element matches when the comparison holds for its value at the innermost level,
+// and when the quantifier of the next level holds over its own array otherwise. Over the elements
+// considered:
+//   - any holds when at least one matches, so never on an empty array
+//   - all holds when there is at least one and every one matches, so never on an empty array
+//   - none holds when no element matches, and so always on an empty array
+//   - count holds when the number of matching elements satisfies the quantifier's comparison, the
+//     number being 0 on an empty array
+//
+// A missing value, or one that is not an array, counts as an empty array, as does one whose
+// elements all fail the array filters.
+//
+// So all collateral[*] and any valuations[*] of data.collateral[*].valuations[*].date olderthandays
+// 90 holds when every collateral item has at least one valuation older than 90 days.
+func (r *run) quantify(c models.Condition, quantifiers []models.ArrayQuantifier, parts [][]string, prefix path, level int) (bool, error) {
+	p := prefix.keys(parts[level])
+	if level == len(parts)-1 {
+		actual, found := get(r.data, p)
+		matched, err := r.compare(actual, found, c.Logical, c.OpValue)
+		if err != nil {
+			return false, err
+		}
+		r.tracef("%s = %s: %t", p, text(actual), matched)
+		return matched, nil
+	}
+	q := quantifiers[level]
+	arr, _ := get(r.data, p)
+	elems, _ := arr.([]interface{})
+	considered, matches := 0, 0
+	for i := range elems {
+		passed, err := r.filters(c.ArrayFilters, parts[level], p.with(i))
+		if err != nil {
+			return false, err
+		}
+		if !passed {
+			continue
+		}
+		considered++
+		matched, err := r.quantify(c, quantifiers, parts, p.with(i), level+1)
+		if err != nil {
+			return false, err
+		}
+		if matched {
+			matches++
+		}
+		// The outcome is known at the first match, or the first mismatch for all
+		switch {
+		case q.Quantifier == quantifierAny && matched:
+			return true, nil
+		case q.Quantifier == quantifierAll && !matched, q.Quantifier == quantifierNone && matched:
+			return false, nil
+		}
+	}
+	switch q.Quantifier {
+	case quantifierAll:
+		return considered > 0, nil
+	case quantifierNone:
+		return true, nil
+	case quantifierCount:
+		held, err := r.compare(float64(matches), true, q.Logical, q.OpValue)
+		if err != nil {
+			return false, err
+		}
+		r.tracef("%s: %d of %d elements considered matched, count %s %s: %t", p, matches, considered, q.Logical, text(normalize(q.OpValue)), held)
+		return held, nil
+	default:
+		return false, nil
+	}
+}
+
+// describe spells the quantifiers of a leaf for the trace, e.g. "all collateral, any valuations of "
+func describe(quantifiers []models.ArrayQuantifier, operator string, parts [][]string) string {
+	var levels []string
+	for i, q := range quantifiers {
+		level := q.Quantifier
+		if q.Quantifier == quantifierCount {
+			level += " " + q.Logical + " " + text(normalize(q.OpValue))
+		}
+		levels = append(levels, level+" "+strings.Join(parts[i], "."))
+	}
+	var out string
+	if strings.EqualFold(operator, "NOT") {
+		out = "not "
+	}
+	if len(levels) > 0 {
+		out += strings.Join(levels, ", ") + " of "
+	}
+	return out
+}
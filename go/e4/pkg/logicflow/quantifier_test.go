@@ -0,0 +1,161 @@
+package logicflow
+
+import "testing"
+
+// quantifierCases cover any, all, none and count over filtered and nested arrays, what each gives
+// on an empty or missing array, and quantifiers that fail
+var quantifierCases = []executorCase{
+	{
+		name:  "any element matches within a filter",
+		facts: `{"orders": [{"status": "open", "total": 50}, {"status": "closed", "total": 900}, {"status": "open", "total": 300}]}`,
+		flow: `[{"condition": {"variable": {"var_key": "data.orders[*].total"}, "logical": "gt", "op_value": 200,
+				"array_filters": [{"array_name": "orders", "property": "status", "logical": "eq", "op_value": "open"}]},
+			"operation_if_true": [{"variable": {"var_key": "data.large_open"}, "operation": "SET", "op_value": true}]}]`,
+		want: map[string]interface{}{"large_open": true},
+	},
+	{
+		name:  "all elements match only among the filtered ones",
+		facts: `{"orders": [{"status": "open", "total": 500}, {"status": "closed", "total": 10}]}`,
+		flow: `[{"condition": {"operator": "ALL", "variable": {"var_key": "data.orders[*].total"}, "logical": "gte", "op_value": 100,
+				"array_filters": [{"array_name": "orders", "property": "status", "logical": "eq", "op_value": "open"}]},
+			"operation_if_true": [{"variable": {"var_key": "data.ok"}, "operation": "SET", "op_value": true}],
+			"operation_if_false": [{"variable": {"var_key": "data.ok"}, "operation": "SET", "op_value": false}]}]`,
+		want: map[string]interface{}{"ok": true},
+	},
+	{
+		name:  "none on nested arrays",
+		facts: `{"orders": [{"items": [{"sku": "A"}, {"sku": "B"}]}, null, {"items": [{"sku": "C"}, null]}]}`,
+		flow: `[{"condition": {"operator": "NOT", "variable": {"var_key": "data.orders[*].items[*].sku"}, "logical": "eq", "op_value": "Z"},
+			"operation_if_true": [{"variable": {"var_key": "data.clean"}, "operation": "SET", "op_value": true}],
+			"operation_if_false": [{"variable": {"var_key": "data.clean"}, "operation": "SET", "op_value": false}]}]`,
+		want: map[string]interface{}{"clean": true},
+	},
+	{
+		name:  "all over an empty selection does not hold",
+		facts: `{"orders": [{"status": "closed", "total": 10}]}`,
+		flow: `[{"condition": {"operator": "ALL", "variable": {"var_key": "data.orders[*].total"}, "logical": "gt", "op_value": 0,
+				"array_filters": [{"array_name": "orders", "property": "status", "logical": "eq", "op_value": "open"}]},
+			"operation_if_true": [{"variable": {"var_key": "data.ok"}, "operation": "SET", "op_value": true}],
+			"operation_if_false": [{"variable": {"var_key": "data.ok"}, "operation": "SET", "op_value": false}]}]`,
+		want: map[string]interface{}{"ok": false},
+	},
+	{
+		name:  "array condition inside a group",
+		facts: `{"vip": false, "orders": [{"total": 5}, {"total": 1500}]}`,
+		flow: `[{"condition": {"operator": "OR", "conditions": [
+				{"variable": {"var_key": "data.vip"}, "logical": "eq", "op_value": true},
+				{"variable": {"var_key": "data.orders[*].total"}, "logical": "gt", "op_value": 1000}]},
+			"operation_if_true": [{"variable": {"var_key": "data.review"}, "operation": "SET", "op_value": "manual"}]}]`,
+		want: map[string]interface{}{"review": "manual"},
+	},
+	{
+		name: "all collateral items where any valuation is older than 90 days",
+		facts: `{"collateral": [{"id": "A", "valuations": [{"date": "2000-01-01"}, {"date": "2999-01-01"}]},
+			{"id": "B", "valuations": [{"date": "2001-06-30T12:00:00Z"}]}]}`,
+		flow: `[{"condition": {"variable": {"var_key": "data.collateral[*].valuations[*].date"}, "logical": "olderthandays", "op_value": 90,
+				"quantifiers": [{"array_name": "collateral", "quantifier": "all"}, {"array_name": "valuations", "quantifier": "any"}]},
+			"operation_if_true": [{"variable": {"var_key": "data.revalue"}, "operation": "SET", "op_value": true}],
+			"operation_if_false": [{"variable": {"var_key": "data.revalue"}, "operation": "SET", "op_value": false}]}]`,
+		want: map[string]interface{}{"revalue": true},
+	},
+	{
+		name: "all collateral items fails on one recently valued item",
+		facts: `{"collateral": [{"id": "A", "valuations": [{"date": "2000-01-01"}]},
+			{"id": "B", "valuations": [{"date": "2999-01-01"}]}]}`,
+		flow: `[{"condition": {"variable": {"var_key": "data.collateral[*].valuations[*].date"}, "logical": "olderthandays", "op_value": 90,
+				"quantifiers": [{"array_name": "collateral", "quantifier": "all"}, {"array_name": "valuations", "quantifier": "any"}]},
+			"operation_if_true": [{"variable": {"var_key": "data.revalue"}, "operation": "SET", "op_value": true}],
+			"operation_if_false": [{"variable": {"var_key": "data.revalue"}, "operation": "SET", "op_value": false}]}]`,
+		want: map[string]interface{}{"revalue": false},
+	},
+	{
+		name:  "count of filtered elements",
+		facts: `{"coupons": [{"status": "paid", "amount": 5}, {"status": "missed", "amount": 5}, {"status": "paid", "amount": 0}, {"status": "paid", "amount": 7}]}`,
+		flow: `[{"condition": {"variable": {"var_key": "data.coupons[*].status"}, "logical": "eq", "op_value": "paid",
+				"array_filters": [{"array_name": "coupons", "property": "amount", "logical": "gt", "op_value": 0}],
+				"quantifiers": [{"array_name": "coupons", "quantifier": "count", "logical": "gte", "op_value": 2}]},
+			"operation_if_true": [{"variable": {"var_key": "data.performing"}, "operation": "SET", "op_value": true}],
+			"operation_if_false": [{"variable": {"var_key": "data.performing"}, "operation": "SET", "op_value": false}]}]`,
+		want: map[string]interface{}{"performing": true},
+	},
+	{
+		name: "count of items none of whose elements match",
+		facts: `{"collateral": [{"valuations": [{"date": "1999-05-01"}, {"date": "2020-01-01"}]},
+			{"valuations": [{"date": "2020-01-01"}]}, {"valuations": []}]}`,
+		flow: `[{"condition": {"variable": {"var_key": "data.collateral[*].valuations[*].date"}, "logical": "before", "op_value": "2001-01-01",
+				"quantifiers": [{"array_name": "collateral", "quantifier": "count", "logical": "eq", "op_value": 2}, {"array_name": "valuations", "quantifier": "none"}]},
+			"operation_if_true": [{"variable": {"var_key": "data.recent"}, "operation": "SET", "op_value": 2}],
+			"operation_if_false": [{"variable": {"var_key": "data.recent"}, "operation": "SET", "op_value": "other"}]}]`,
+		want: map[string]interface{}{"recent": 2.0},
+	},
+	{
+		name:  "none over an empty array holds",
+		facts: `{"defaults": []}`,
+		flow: `[{"condition": {"variable": {"var_key": "data.defaults[*].amount"}, "logical": "gt", "op_value": 0,
+				"quantifiers": [{"array_name": "defaults", "quantifier": "none"}]},
+			"operation_if_true": [{"variable": {"var_key": "data.clean"}, "operation": "SET", "op_value": true}],
+			"operation_if_false": [{"variable": {"var_key": "data.clean"}, "operation": "SET", "op_value": false}]}]`,
+		want: map[string]interface{}{"clean": true},
+	},
+	{
+		name:  "NOT negates explicit quantifiers",
+		facts: `{"items": [{"qty": 1}, {"qty": 5}]}`,
+		flow: `[{"condition": {"operator": "NOT", "variable": {"var_key": "data.items[*].qty"}, "logical": "gt", "op_value": 0,
+				"quantifiers": [{"array_name": "data.items[*]", "quantifier": "ALL"}]},
+			"operation_if_true": [{"variable": {"var_key": "data.some_empty"}, "operation": "SET", "op_value": true}],
+			"operation_if_false": [{"variable": {"var_key": "data.some_empty"}, "operation": "SET", "op_value": false}]}]`,
+		want: map[string]interface{}{"some_empty": false},
+	},
+	{
+		name:  "any over an empty array does not hold",
+		facts: `{"orders": []}`,
+		flow: `[{"condition": {"variable": {"var_key": "data.orders[*].total"}, "logical": "gt", "op_value": 0,
+				"quantifiers": [{"array_name": "orders", "quantifier": "any"}]},
+			"operation_if_true": [{"variable": {"var_key": "data.found"}, "operation": "SET", "op_value": true}],
+			"operation_if_false": [{"variable": {"var_key": "data.found"}, "operation": "SET", "op_value": false}]}]`,
+		want: map[string]interface{}{"found": false},
+	},
+	{
+		name:  "all over a missing array does not hold",
+		facts: `{}`,
+		flow: `[{"condition": {"variable": {"var_key": "data.orders[*].total"}, "logical": "gt", "op_value": 0,
+				"quantifiers": [{"array_name": "orders", "quantifier": "all"}]},
+			"operation_if_true": [{"variable": {"var_key": "data.ok"}, "operation": "SET", "op_value": true}],
+			"operation_if_false": [{"variable": {"var_key": "data.ok"}, "operation": "SET", "op_value": false}]}]`,
+		want: map[string]interface{}{"ok": false},
+	},
+	{
+		name:  "count over an empty array counts 0",
+		facts: `{"coupons": []}`,
+		flow: `[{"condition": {"variable": {"var_key": "data.coupons[*].status"}, "logical": "eq", "op_value": "paid",
+				"quantifiers": [{"array_name": "coupons", "quantifier": "count", "logical": "eq", "op_value": 0}]},
+			"operation_if_true": [{"variable": {"var_key": "data.unpaid"}, "operation": "SET", "op_value": true}],
+			"operation_if_false": [{"variable": {"var_key": "data.unpaid"}, "operation": "SET", "op_value": false}]}]`,
+		want: map[string]interface{}{"unpaid": true},
+	},
+	{
+		name:    "unknown quantifier fails",
+		facts:   `{"items": [1]}`,
+		flow:    `[{"condition": {"variable": {"var_key": "data.items[*]"}, "logical": "gt", "op_value": 0, "quantifiers": [{"array_name": "items", "quantifier": "most"}]}}]`,
+		wantErr: true,
+	},
+	{
+		name:    "count without a comparison fails",
+		facts:   `{"items": [1]}`,
+		flow:    `[{"condition": {"variable": {"var_key": "data.items[*]"}, "logical": "gt", "op_value": 0, "quantifiers": [{"array_name": "items", "quantifier": "count", "op_value": 1}]}}]`,
+		wantErr: true,
+	},
+	{
+		name:    "quantifier of an array the key does not walk fails",
+		facts:   `{"items": [1]}`,
+		flow:    `[{"condition": {"variable": {"var_key": "data.items[*]"}, "logical": "gt", "op_value": 0, "quantifiers": [{"array_name": "orders", "quantifier": "all"}]}}]`,
+		wantErr: true,
+	},
+}
+
+// TestQuantify runs the executor on conditions over arrays
+func TestQuantify(t *testing.T) {
+	for _, tc := range quantifierCases {
+		t.Run(tc.name, tc.run)
+	}
+}
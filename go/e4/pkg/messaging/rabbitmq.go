@@ -81,10 +81,18 @@ func (r *RabbitMQ) Publish(ctx context.Context, routingKey string, message inter
 }
 
 func (r *RabbitMQ) Consume(queueName string, handler func([]byte) error) error {
+	return r.ConsumeWithRoutingKey(queueName, "#", handler)
+}
+
+// ConsumeWithRoutingKey binds the queue to the exchange for the given routing key pattern only,
+// so a consumer that publishes back to the same exchange does not receive its own messages. A
+// message the handler fails on is requeued once; when its redelivery fails too it is rejected,
+// which drops it or routes it to the queue's dead letter exchange, rather than looping.
+func (r *RabbitMQ) ConsumeWithRoutingKey(queueName, routingKey string, handler func([]byte) error) error {
 	// Bind queue to exchange
 	err := r.channel.QueueBind(
 		queueName,
-		"#", // routing key pattern
+		routingKey, // routing key pattern
 		r.config.Exchange,
 		false,
 		nil,
@@ -109,6 +117,11 @@ func (r *RabbitMQ) Consume(queueName string, handler func([]byte) error) error {
 	go func() {
 		for msg := range msgs {
 			if err := handler(msg.Body); err != nil {
+				if msg.Redelivered {
+					log.Printf("Error handling redelivered message, rejecting it: %v", err)
+					msg.Nack(false, false)
+					continue
+				}
 				log.Printf("Error handling message: %v", err)
 				msg.Nack(false, true) // requeue on error
 			} else {
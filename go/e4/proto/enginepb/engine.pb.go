@@ -0,0 +1,589 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v5.28.3
+// source: engine.proto
+
+package enginepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type InvokeRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	NimbId string                 `protobuf:"bytes,1,opt,name=nimb_id,json=nimbId,proto3" json:"nimb_id,omitempty"`
+	// 0 lets the engine's traffic split pick the version.
+	Version int32            `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Facts   *structpb.Struct `protobuf:"bytes,3,opt,name=facts,proto3" json:"facts,omitempty"`
+	Debug   bool             `protobuf:"varint,4,opt,name=debug,proto3" json:"debug,omitempty"`
+	// Version alias such as prod; takes precedence over version.
+	Alias         string `protobuf:"bytes,5,opt,name=alias,proto3" json:"alias,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InvokeRequest) Reset() {
+	*x = InvokeRequest{}
+	mi := &file_engine_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InvokeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InvokeRequest) ProtoMessage() {}
+
+func (x *InvokeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_engine_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvokeRequest.ProtoReflect.Descriptor instead.
+func (*InvokeRequest) Descriptor() ([]byte, []int) {
+	return file_engine_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *InvokeRequest) GetNimbId() string {
+	if x != nil {
+		return x.NimbId
+	}
+	return ""
+}
+
+func (x *InvokeRequest) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *InvokeRequest) GetFacts() *structpb.Struct {
+	if x != nil {
+		return x.Facts
+	}
+	return nil
+}
+
+func (x *InvokeRequest) GetDebug() bool {
+	if x != nil {
+		return x.Debug
+	}
+	return false
+}
+
+func (x *InvokeRequest) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+type InvokeResponse struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Data        *structpb.Struct       `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Logs        []string               `protobuf:"bytes,2,rep,name=logs,proto3" json:"logs,omitempty"`
+	TimeTakenMs int64                  `protobuf:"varint,3,opt,name=time_taken_ms,json=timeTakenMs,proto3" json:"time_taken_ms,omitempty"`
+	// Execution history record ID, empty when history is disabled.
+	ExecutionId string `protobuf:"bytes,4,opt,name=execution_id,json=executionId,proto3" json:"execution_id,omitempty"`
+	// Version that served the request; chosen by the engine's traffic split when the request names none.
+	Version       int32 `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InvokeResponse) Reset() {
+	*x = InvokeResponse{}
+	mi := &file_engine_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InvokeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InvokeResponse) ProtoMessage() {}
+
+func (x *InvokeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_engine_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvokeResponse.ProtoReflect.Descriptor instead.
+func (*InvokeResponse) Descriptor() ([]byte, []int) {
+	return file_engine_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *InvokeResponse) GetData() *structpb.Struct {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *InvokeResponse) GetLogs() []string {
+	if x != nil {
+		return x.Logs
+	}
+	return nil
+}
+
+func (x *InvokeResponse) GetTimeTakenMs() int64 {
+	if x != nil {
+		return x.TimeTakenMs
+	}
+	return 0
+}
+
+func (x *InvokeResponse) GetExecutionId() string {
+	if x != nil {
+		return x.ExecutionId
+	}
+	return ""
+}
+
+func (x *InvokeResponse) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type InvokeBatchRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	NimbId  string                 `protobuf:"bytes,1,opt,name=nimb_id,json=nimbId,proto3" json:"nimb_id,omitempty"`
+	Version int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Facts   []*structpb.Struct     `protobuf:"bytes,3,rep,name=facts,proto3" json:"facts,omitempty"`
+	Workers int32                  `protobuf:"varint,4,opt,name=workers,proto3" json:"workers,omitempty"`
+	Debug   bool                   `protobuf:"varint,5,opt,name=debug,proto3" json:"debug,omitempty"`
+	// Version alias such as prod; takes precedence over version.
+	Alias string `protobuf:"bytes,6,opt,name=alias,proto3" json:"alias,omitempty"`
+	// Minor version of version; 0 selects the active one.
+	MinorVersion  int32 `protobuf:"varint,7,opt,name=minor_version,json=minorVersion,proto3" json:"minor_version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InvokeBatchRequest) Reset() {
+	*x = InvokeBatchRequest{}
+	mi := &file_engine_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InvokeBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InvokeBatchRequest) ProtoMessage() {}
+
+func (x *InvokeBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_engine_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvokeBatchRequest.ProtoReflect.Descriptor instead.
+func (*InvokeBatchRequest) Descriptor() ([]byte, []int) {
+	return file_engine_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *InvokeBatchRequest) GetNimbId() string {
+	if x != nil {
+		return x.NimbId
+	}
+	return ""
+}
+
+func (x *InvokeBatchRequest) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *InvokeBatchRequest) GetFacts() []*structpb.Struct {
+	if x != nil {
+		return x.Facts
+	}
+	return nil
+}
+
+func (x *InvokeBatchRequest) GetWorkers() int32 {
+	if x != nil {
+		return x.Workers
+	}
+	return 0
+}
+
+func (x *InvokeBatchRequest) GetDebug() bool {
+	if x != nil {
+		return x.Debug
+	}
+	return false
+}
+
+func (x *InvokeBatchRequest) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *InvokeBatchRequest) GetMinorVersion() int32 {
+	if x != nil {
+		return x.MinorVersion
+	}
+	return 0
+}
+
+type BatchResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Data          *structpb.Struct       `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	Logs          []string               `protobuf:"bytes,4,rep,name=logs,proto3" json:"logs,omitempty"`
+	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	TimeTakenMs   int64                  `protobuf:"varint,6,opt,name=time_taken_ms,json=timeTakenMs,proto3" json:"time_taken_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchResult) Reset() {
+	*x = BatchResult{}
+	mi := &file_engine_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchResult) ProtoMessage() {}
+
+func (x *BatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_engine_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchResult.ProtoReflect.Descriptor instead.
+func (*BatchResult) Descriptor() ([]byte, []int) {
+	return file_engine_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BatchResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BatchResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *BatchResult) GetData() *structpb.Struct {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *BatchResult) GetLogs() []string {
+	if x != nil {
+		return x.Logs
+	}
+	return nil
+}
+
+func (x *BatchResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *BatchResult) GetTimeTakenMs() int64 {
+	if x != nil {
+		return x.TimeTakenMs
+	}
+	return 0
+}
+
+type ValidateRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	NimbId  string                 `protobuf:"bytes,1,opt,name=nimb_id,json=nimbId,proto3" json:"nimb_id,omitempty"`
+	Version int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	// Version alias such as prod; takes precedence over version.
+	Alias string `protobuf:"bytes,3,opt,name=alias,proto3" json:"alias,omitempty"`
+	// Minor version of version; 0 selects the active one.
+	MinorVersion  int32 `protobuf:"varint,4,opt,name=minor_version,json=minorVersion,proto3" json:"minor_version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateRequest) Reset() {
+	*x = ValidateRequest{}
+	mi := &file_engine_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateRequest) ProtoMessage() {}
+
+func (x *ValidateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_engine_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateRequest.ProtoReflect.Descriptor instead.
+func (*ValidateRequest) Descriptor() ([]byte, []int) {
+	return file_engine_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ValidateRequest) GetNimbId() string {
+	if x != nil {
+		return x.NimbId
+	}
+	return ""
+}
+
+func (x *ValidateRequest) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ValidateRequest) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *ValidateRequest) GetMinorVersion() int32 {
+	if x != nil {
+		return x.MinorVersion
+	}
+	return 0
+}
+
+type ValidateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Errors        []string               `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateResponse) Reset() {
+	*x = ValidateResponse{}
+	mi := &file_engine_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateResponse) ProtoMessage() {}
+
+func (x *ValidateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_engine_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateResponse.ProtoReflect.Descriptor instead.
+func (*ValidateResponse) Descriptor() ([]byte, []int) {
+	return file_engine_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ValidateResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateResponse) GetErrors() []string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+var File_engine_proto protoreflect.FileDescriptor
+
+const file_engine_proto_rawDesc = "" +
+	"\n" +
+	"\fengine.proto\x12\x10nimbus.engine.v1\x1a\x1cgoogle/protobuf/struct.proto\"\x9d\x01\n" +
+	"\rInvokeRequest\x12\x17\n" +
+	"\animb_id\x18\x01 \x01(\tR\x06nimbId\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\x12-\n" +
+	"\x05facts\x18\x03 \x01(\v2\x17.google.protobuf.StructR\x05facts\x12\x14\n" +
+	"\x05debug\x18\x04 \x01(\bR\x05debug\x12\x14\n" +
+	"\x05alias\x18\x05 \x01(\tR\x05alias\"\xb2\x01\n" +
+	"\x0eInvokeResponse\x12+\n" +
+	"\x04data\x18\x01 \x01(\v2\x17.google.protobuf.StructR\x04data\x12\x12\n" +
+	"\x04logs\x18\x02 \x03(\tR\x04logs\x12\"\n" +
+	"\rtime_taken_ms\x18\x03 \x01(\x03R\vtimeTakenMs\x12!\n" +
+	"\fexecution_id\x18\x04 \x01(\tR\vexecutionId\x12\x18\n" +
+	"\aversion\x18\x05 \x01(\x05R\aversion\"\xe1\x01\n" +
+	"\x12InvokeBatchRequest\x12\x17\n" +
+	"\animb_id\x18\x01 \x01(\tR\x06nimbId\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\x12-\n" +
+	"\x05facts\x18\x03 \x03(\v2\x17.google.protobuf.StructR\x05facts\x12\x18\n" +
+	"\aworkers\x18\x04 \x01(\x05R\aworkers\x12\x14\n" +
+	"\x05debug\x18\x05 \x01(\bR\x05debug\x12\x14\n" +
+	"\x05alias\x18\x06 \x01(\tR\x05alias\x12#\n" +
+	"\rminor_version\x18\a \x01(\x05R\fminorVersion\"\xb6\x01\n" +
+	"\vBatchResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12+\n" +
+	"\x04data\x18\x03 \x01(\v2\x17.google.protobuf.StructR\x04data\x12\x12\n" +
+	"\x04logs\x18\x04 \x03(\tR\x04logs\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x12\"\n" +
+	"\rtime_taken_ms\x18\x06 \x01(\x03R\vtimeTakenMs\"\x7f\n" +
+	"\x0fValidateRequest\x12\x17\n" +
+	"\animb_id\x18\x01 \x01(\tR\x06nimbId\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\x12\x14\n" +
+	"\x05alias\x18\x03 \x01(\tR\x05alias\x12#\n" +
+	"\rminor_version\x18\x04 \x01(\x05R\fminorVersion\"@\n" +
+	"\x10ValidateResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x16\n" +
+	"\x06errors\x18\x02 \x03(\tR\x06errors2\x85\x02\n" +
+	"\rEngineService\x12K\n" +
+	"\x06Invoke\x12\x1f.nimbus.engine.v1.InvokeRequest\x1a .nimbus.engine.v1.InvokeResponse\x12T\n" +
+	"\vInvokeBatch\x12$.nimbus.engine.v1.InvokeBatchRequest\x1a\x1d.nimbus.engine.v1.BatchResult0\x01\x12Q\n" +
+	"\bValidate\x12!.nimbus.engine.v1.ValidateRequest\x1a\".nimbus.engine.v1.ValidateResponseB_\n" +
+	"\x14com.nimbus.engine.v1P\x01ZEgithub.com/prithvirajv06/nimbus-uta/go/engine/proto/enginepb;enginepbb\x06proto3"
+
+var (
+	file_engine_proto_rawDescOnce sync.Once
+	file_engine_proto_rawDescData []byte
+)
+
+func file_engine_proto_rawDescGZIP() []byte {
+	file_engine_proto_rawDescOnce.Do(func() {
+		file_engine_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_engine_proto_rawDesc), len(file_engine_proto_rawDesc)))
+	})
+	return file_engine_proto_rawDescData
+}
+
+var file_engine_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_engine_proto_goTypes = []any{
+	(*InvokeRequest)(nil),      // 0: nimbus.engine.v1.InvokeRequest
+	(*InvokeResponse)(nil),     // 1: nimbus.engine.v1.InvokeResponse
+	(*InvokeBatchRequest)(nil), // 2: nimbus.engine.v1.InvokeBatchRequest
+	(*BatchResult)(nil),        // 3: nimbus.engine.v1.BatchResult
+	(*ValidateRequest)(nil),    // 4: nimbus.engine.v1.ValidateRequest
+	(*ValidateResponse)(nil),   // 5: nimbus.engine.v1.ValidateResponse
+	(*structpb.Struct)(nil),    // 6: google.protobuf.Struct
+}
+var file_engine_proto_depIdxs = []int32{
+	6, // 0: nimbus.engine.v1.InvokeRequest.facts:type_name -> google.protobuf.Struct
+	6, // 1: nimbus.engine.v1.InvokeResponse.data:type_name -> google.protobuf.Struct
+	6, // 2: nimbus.engine.v1.InvokeBatchRequest.facts:type_name -> google.protobuf.Struct
+	6, // 3: nimbus.engine.v1.BatchResult.data:type_name -> google.protobuf.Struct
+	0, // 4: nimbus.engine.v1.EngineService.Invoke:input_type -> nimbus.engine.v1.InvokeRequest
+	2, // 5: nimbus.engine.v1.EngineService.InvokeBatch:input_type -> nimbus.engine.v1.InvokeBatchRequest
+	4, // 6: nimbus.engine.v1.EngineService.Validate:input_type -> nimbus.engine.v1.ValidateRequest
+	1, // 7: nimbus.engine.v1.EngineService.Invoke:output_type -> nimbus.engine.v1.InvokeResponse
+	3, // 8: nimbus.engine.v1.EngineService.InvokeBatch:output_type -> nimbus.engine.v1.BatchResult
+	5, // 9: nimbus.engine.v1.EngineService.Validate:output_type -> nimbus.engine.v1.ValidateResponse
+	7, // [7:10] is the sub-list for method output_type
+	4, // [4:7] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_engine_proto_init() }
+func file_engine_proto_init() {
+	if File_engine_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_engine_proto_rawDesc), len(file_engine_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_engine_proto_goTypes,
+		DependencyIndexes: file_engine_proto_depIdxs,
+		MessageInfos:      file_engine_proto_msgTypes,
+	}.Build()
+	File_engine_proto = out.File
+	file_engine_proto_goTypes = nil
+	file_engine_proto_depIdxs = nil
+}